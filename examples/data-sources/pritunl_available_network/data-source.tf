@@ -0,0 +1,9 @@
+data "pritunl_available_network" "example" {
+  network_pool          = "10.100.0.0/16"
+  network_prefix_length = 24
+}
+
+resource "pritunl_server" "example" {
+  name    = "example"
+  network = data.pritunl_available_network.example.network
+}
@@ -0,0 +1,8 @@
+data "pritunl_host_usage" "node_1" {
+  hostname = "node-1.internal"
+  period   = "30m"
+}
+
+output "node_1_busy" {
+  value = data.pritunl_host_usage.node_1.latest_cpu > 80
+}
@@ -0,0 +1,9 @@
+data "pritunl_hosts" "eu_west" {
+  availability_group = "eu-west"
+  status             = "online"
+}
+
+resource "pritunl_server" "example" {
+  name     = "example"
+  host_ids = data.pritunl_hosts.eu_west.online_ids
+}
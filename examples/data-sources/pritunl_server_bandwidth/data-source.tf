@@ -0,0 +1,8 @@
+data "pritunl_server_bandwidth" "example" {
+  server_id = pritunl_server.example.id
+  period    = "2h"
+}
+
+output "received_bytes" {
+  value = data.pritunl_server_bandwidth.example.received_total
+}
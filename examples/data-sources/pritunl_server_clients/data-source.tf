@@ -0,0 +1,7 @@
+data "pritunl_server_clients" "example" {
+  server_id = pritunl_server.example.id
+}
+
+output "connected_users" {
+  value = distinct(data.pritunl_server_clients.example.clients[*].user_name)
+}
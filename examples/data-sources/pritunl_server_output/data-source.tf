@@ -0,0 +1,8 @@
+data "pritunl_server_output" "example" {
+  server_id = pritunl_server.example.id
+  max_lines = 50
+}
+
+output "server_output" {
+  value = join("\n", data.pritunl_server_output.example.output)
+}
@@ -0,0 +1,16 @@
+resource "pritunl_user" "example" {
+  name            = "example"
+  organization_id = pritunl_organization.example.id
+  audit           = true
+}
+
+data "pritunl_user_audit" "example" {
+  user_id         = pritunl_user.example.id
+  organization_id = pritunl_user.example.organization_id
+  since           = "2026-10-01T00:00:00Z"
+  types           = ["user_profile"]
+}
+
+output "profile_downloads" {
+  value = data.pritunl_user_audit.example.events[*].remote_address
+}
@@ -0,0 +1,19 @@
+resource "time_rotating" "api_key" {
+  rotation_days = 90
+}
+
+resource "pritunl_administrator" "automation" {
+  username   = "automation"
+  password   = var.automation_password
+  super_user = false
+  auth_api   = true
+
+  api_key_rotation_triggers = {
+    rotated = time_rotating.api_key.id
+  }
+}
+
+output "automation_token" {
+  value     = pritunl_administrator.automation.token
+  sensitive = true
+}
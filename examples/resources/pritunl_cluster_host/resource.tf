@@ -0,0 +1,6 @@
+resource "pritunl_cluster_host" "node" {
+  hostname           = aws_instance.vpn.private_dns
+  public_address     = aws_eip.vpn.public_ip
+  availability_group = aws_instance.vpn.availability_zone
+  remove_on_destroy  = true
+}
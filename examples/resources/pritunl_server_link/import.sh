@@ -0,0 +1 @@
+terraform import pritunl_server_link.eu_us ${SERVER_ID}-${LINKED_SERVER_ID}
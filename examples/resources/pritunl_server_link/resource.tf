@@ -0,0 +1,4 @@
+resource "pritunl_server_link" "eu_us" {
+  server_id        = pritunl_server.eu.id
+  linked_server_id = pritunl_server.us.id
+}
@@ -0,0 +1,5 @@
+resource "pritunl_settings" "this" {
+  additional_settings = {
+    some_setting = jsonencode(true)
+  }
+}
@@ -0,0 +1 @@
+terraform import pritunl_settings.this settings
@@ -0,0 +1,7 @@
+resource "pritunl_settings" "this" {
+  public_address  = "vpn.example.com"
+  acme_domain     = "vpn.example.com"
+  theme           = "dark"
+  auditing        = true
+  restrict_import = true
+}
@@ -0,0 +1 @@
+terraform import pritunl_sso.this sso
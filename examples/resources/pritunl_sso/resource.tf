@@ -0,0 +1,18 @@
+resource "pritunl_sso" "this" {
+  default_organization_id = pritunl_organization.employees.id
+
+  saml {
+    sso_url     = "https://example.okta.com/app/example/exk1/sso/saml"
+    issuer_url  = "http://www.okta.com/exk1"
+    certificate = file("okta.pem")
+
+    okta_app_id = "0oa1"
+    okta_token  = var.okta_token
+  }
+
+  duo {
+    integration_key = "DIXXXXXXXXXXXXXXXXXX"
+    secret_key      = var.duo_secret_key
+    api_hostname    = "api-1234.duosecurity.com"
+  }
+}
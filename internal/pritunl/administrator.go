@@ -0,0 +1,18 @@
+package pritunl
+
+// Administrator is an account of the web console. Token and Secret are the API key of the administrator,
+// they are only accepted by the API when AuthAPI is enabled.
+type Administrator struct {
+	ID        string `json:"id,omitempty"`
+	Username  string `json:"username"`
+	Password  string `json:"password,omitempty"`
+	YubikeyID string `json:"yubikey_id"`
+	OtpAuth   bool   `json:"otp_auth"`
+	OtpSecret string `json:"otp_secret,omitempty"`
+	AuthAPI   bool   `json:"auth_api"`
+	Token     string `json:"token,omitempty"`
+	Secret    string `json:"secret,omitempty"`
+	Disabled  bool   `json:"disabled"`
+	SuperUser bool   `json:"super_user"`
+	Default   bool   `json:"default,omitempty"`
+}
@@ -0,0 +1,460 @@
+package pritunl
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// cachingClient memoizes the list endpoints of the underlying client and coalesces
+// concurrent identical requests. Cached collections are invalidated on every write to them.
+// The provider creates a new client for every plan or apply, so the cache lives as long as the run.
+type cachingClient struct {
+	Client
+
+	mu         sync.Mutex
+	entries    map[string]interface{}
+	generation uint64
+	group      singleflight.Group
+}
+
+func NewCachingClient(c Client) Client {
+	return &cachingClient{
+		Client:  c,
+		entries: make(map[string]interface{}),
+	}
+}
+
+// cachedList returns the memoized list stored with the key or fetches it.
+// Concurrent calls with the same key share a single request.
+func cachedList[T any](c *cachingClient, key string, fetch func() ([]T, error)) ([]T, error) {
+	c.mu.Lock()
+	if v, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		return copyList(v.([]T)), nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	// requests started before a write are not shared with the calls made after it
+	v, err, _ := c.group.Do(fmt.Sprintf("%s@%d", key, generation), func() (interface{}, error) {
+		list, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		// the collection could have been changed while the request was in flight
+		if c.generation == generation {
+			c.entries[key] = list
+		}
+		c.mu.Unlock()
+
+		return list, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return copyList(v.([]T)), nil
+}
+
+// coalesced shares a single request between concurrent calls with the same key without memoizing the result
+func coalesced[T any](c *cachingClient, key string, fetch func() (*T, error)) (*T, error) {
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	v, err, _ := c.group.Do(fmt.Sprintf("%s@%d", key, generation), func() (interface{}, error) {
+		return fetch()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := *(v.(*T))
+	return &result, nil
+}
+
+// copyList prevents callers from modifying the cached list
+func copyList[T any](list []T) []T {
+	if list == nil {
+		return nil
+	}
+
+	result := make([]T, len(list))
+	copy(result, list)
+	return result
+}
+
+// invalidate removes the cached entries which keys start with one of the prefixes
+func (c *cachingClient) invalidate(prefixes ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for key := range c.entries {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				delete(c.entries, key)
+				break
+			}
+		}
+	}
+}
+
+// InvalidateCache removes the cached responses, e.g. before reading a list which could be changed by another client
+func (c *cachingClient) InvalidateCache(prefixes ...string) {
+	c.invalidate(prefixes...)
+}
+
+func (c *cachingClient) GetOrganizations() ([]Organization, error) {
+	return cachedList(c, "/organization", c.Client.GetOrganizations)
+}
+
+func (c *cachingClient) GetOrganization(id string) (*Organization, error) {
+	return coalesced(c, fmt.Sprintf("/organization/%s", id), func() (*Organization, error) {
+		return c.Client.GetOrganization(id)
+	})
+}
+
+func (c *cachingClient) CreateOrganization(name string) (*Organization, error) {
+	defer c.invalidate("/organization")
+	return c.Client.CreateOrganization(name)
+}
+
+func (c *cachingClient) UpdateOrganization(id string, organization *Organization) error {
+	defer c.invalidate("/organization", "/server")
+	return c.Client.UpdateOrganization(id, organization)
+}
+
+func (c *cachingClient) DeleteOrganization(id string) error {
+	defer c.invalidate("/organization", "/server")
+	return c.Client.DeleteOrganization(id)
+}
+
+func (c *cachingClient) GetUser(id string, orgId string) (*User, error) {
+	return coalesced(c, fmt.Sprintf("/user/%s/%s", orgId, id), func() (*User, error) {
+		return c.Client.GetUser(id, orgId)
+	})
+}
+
+func (c *cachingClient) CreateUser(newUser User) (*User, error) {
+	defer c.invalidate("/user")
+	return c.Client.CreateUser(newUser)
+}
+
+func (c *cachingClient) UpdateUser(id string, user *User) error {
+	defer c.invalidate("/user")
+	return c.Client.UpdateUser(id, user)
+}
+
+func (c *cachingClient) DeleteUser(id string, orgId string) error {
+	defer c.invalidate("/user")
+	return c.Client.DeleteUser(id, orgId)
+}
+
+func (c *cachingClient) GetServers() ([]Server, error) {
+	return cachedList(c, "/server", c.Client.GetServers)
+}
+
+func (c *cachingClient) GetServer(id string) (*Server, error) {
+	return coalesced(c, fmt.Sprintf("/server/%s", id), func() (*Server, error) {
+		return c.Client.GetServer(id)
+	})
+}
+
+func (c *cachingClient) CreateServer(serverData map[string]interface{}) (*Server, error) {
+	defer c.invalidate("/server")
+	return c.Client.CreateServer(serverData)
+}
+
+func (c *cachingClient) UpdateServer(id string, server *Server) error {
+	defer c.invalidate("/server")
+	return c.Client.UpdateServer(id, server)
+}
+
+func (c *cachingClient) DeleteServer(id string) error {
+	defer c.invalidate("/server", "/host")
+	return c.Client.DeleteServer(id)
+}
+
+func (c *cachingClient) GetOrganizationsByServer(serverId string) ([]Organization, error) {
+	return cachedList(c, fmt.Sprintf("/server/%s/organization", serverId), func() ([]Organization, error) {
+		return c.Client.GetOrganizationsByServer(serverId)
+	})
+}
+
+func (c *cachingClient) AttachOrganizationToServer(organizationId, serverId string) error {
+	defer c.invalidate("/server")
+	return c.Client.AttachOrganizationToServer(organizationId, serverId)
+}
+
+func (c *cachingClient) DetachOrganizationFromServer(organizationId, serverId string) error {
+	defer c.invalidate("/server")
+	return c.Client.DetachOrganizationFromServer(organizationId, serverId)
+}
+
+func (c *cachingClient) GetRoutesByServer(serverId string) ([]Route, error) {
+	return cachedList(c, fmt.Sprintf("/server/%s/route", serverId), func() ([]Route, error) {
+		return c.Client.GetRoutesByServer(serverId)
+	})
+}
+
+func (c *cachingClient) AddRouteToServer(serverId string, route Route) error {
+	defer c.invalidate("/server")
+	return c.Client.AddRouteToServer(serverId, route)
+}
+
+func (c *cachingClient) AddRoutesToServer(serverId string, routes []Route) error {
+	defer c.invalidate("/server")
+	return c.Client.AddRoutesToServer(serverId, routes)
+}
+
+func (c *cachingClient) DeleteRouteFromServer(serverId string, route Route) error {
+	defer c.invalidate("/server")
+	return c.Client.DeleteRouteFromServer(serverId, route)
+}
+
+func (c *cachingClient) UpdateRouteOnServer(serverId string, route Route) error {
+	defer c.invalidate("/server")
+	return c.Client.UpdateRouteOnServer(serverId, route)
+}
+
+func (c *cachingClient) GetHosts() ([]Host, error) {
+	return cachedList(c, "/host", c.Client.GetHosts)
+}
+
+func (c *cachingClient) GetClusterHost(id string) (*Host, error) {
+	return coalesced(c, fmt.Sprintf("/host/%s", id), func() (*Host, error) {
+		return c.Client.GetClusterHost(id)
+	})
+}
+
+func (c *cachingClient) UpdateClusterHost(id string, host *Host) error {
+	defer c.invalidate("/host", "/server")
+	return c.Client.UpdateClusterHost(id, host)
+}
+
+func (c *cachingClient) DeleteClusterHost(id string) error {
+	defer c.invalidate("/host", "/server")
+	return c.Client.DeleteClusterHost(id)
+}
+
+func (c *cachingClient) GetAdministrators() ([]Administrator, error) {
+	return cachedList(c, "/admin", c.Client.GetAdministrators)
+}
+
+func (c *cachingClient) GetAdministrator(id string) (*Administrator, error) {
+	return coalesced(c, fmt.Sprintf("/admin/%s", id), func() (*Administrator, error) {
+		return c.Client.GetAdministrator(id)
+	})
+}
+
+func (c *cachingClient) CreateAdministrator(newAdministrator Administrator) (*Administrator, error) {
+	defer c.invalidate("/admin")
+	return c.Client.CreateAdministrator(newAdministrator)
+}
+
+func (c *cachingClient) UpdateAdministrator(id string, administrator *Administrator) error {
+	defer c.invalidate("/admin")
+	return c.Client.UpdateAdministrator(id, administrator)
+}
+
+func (c *cachingClient) RegenerateAdministratorAPIKey(id string) (*Administrator, error) {
+	defer c.invalidate("/admin")
+	return c.Client.RegenerateAdministratorAPIKey(id)
+}
+
+func (c *cachingClient) DeleteAdministrator(id string) error {
+	defer c.invalidate("/admin")
+	return c.Client.DeleteAdministrator(id)
+}
+
+func (c *cachingClient) GetHostsByServer(serverId string) ([]Host, error) {
+	return cachedList(c, fmt.Sprintf("/server/%s/host", serverId), func() ([]Host, error) {
+		return c.Client.GetHostsByServer(serverId)
+	})
+}
+
+func (c *cachingClient) AttachHostToServer(hostId, serverId string) error {
+	defer c.invalidate("/server", "/host")
+	return c.Client.AttachHostToServer(hostId, serverId)
+}
+
+func (c *cachingClient) DetachHostFromServer(hostId, serverId string) error {
+	defer c.invalidate("/server", "/host")
+	return c.Client.DetachHostFromServer(hostId, serverId)
+}
+
+func (c *cachingClient) GetServerLinks(serverId string) ([]ServerLink, error) {
+	return cachedList(c, fmt.Sprintf("/server/%s/link", serverId), func() ([]ServerLink, error) {
+		return c.Client.GetServerLinks(serverId)
+	})
+}
+
+func (c *cachingClient) AttachServerLink(serverId, linkServerId string, useLocalAddress bool) error {
+	defer c.invalidate("/server")
+	return c.Client.AttachServerLink(serverId, linkServerId, useLocalAddress)
+}
+
+func (c *cachingClient) DetachServerLink(serverId, linkServerId string) error {
+	defer c.invalidate("/server")
+	return c.Client.DetachServerLink(serverId, linkServerId)
+}
+
+func (c *cachingClient) StartServer(serverId string) error {
+	defer c.invalidate("/server", "/host")
+	return c.Client.StartServer(serverId)
+}
+
+func (c *cachingClient) StopServer(serverId string) error {
+	defer c.invalidate("/server", "/host")
+	return c.Client.StopServer(serverId)
+}
+
+func (c *cachingClient) GetLinks() ([]Link, error) {
+	return cachedList(c, "/link", c.Client.GetLinks)
+}
+
+// GetLink looks for the link in the cached list, the underlying client would request the list again
+func (c *cachingClient) GetLink(id string) (*Link, error) {
+	links, err := c.GetLinks()
+	if err != nil {
+		return nil, fmt.Errorf("GetLink: Error on GetLinks: %s", err)
+	}
+
+	var link Link
+	for _, v := range links {
+		if v.ID == id {
+			link = v
+		}
+	}
+
+	return &link, nil
+}
+
+func (c *cachingClient) CreateLink(newLink Link) (*Link, error) {
+	defer c.invalidate("/link")
+	return c.Client.CreateLink(newLink)
+}
+
+func (c *cachingClient) UpdateLink(id string, link *Link) error {
+	defer c.invalidate("/link")
+	return c.Client.UpdateLink(id, link)
+}
+
+func (c *cachingClient) DeleteLink(id string) error {
+	defer c.invalidate("/link")
+	return c.Client.DeleteLink(id)
+}
+
+func (c *cachingClient) GetLocations(linkId string) ([]Location, error) {
+	return cachedList(c, fmt.Sprintf("/link/%s/location", linkId), func() ([]Location, error) {
+		return c.Client.GetLocations(linkId)
+	})
+}
+
+// GetLocation looks for the location in the cached list of the link locations
+func (c *cachingClient) GetLocation(id string, linkId string) (*Location, error) {
+	locations, err := c.GetLocations(linkId)
+	if err != nil {
+		return nil, fmt.Errorf("GetLocation: Error on getting locations: %s", err)
+	}
+
+	var location Location
+	for _, v := range locations {
+		if v.ID == id {
+			location = v
+		}
+	}
+
+	return &location, nil
+}
+
+func (c *cachingClient) CreateLocation(newLocation Location) (*Location, error) {
+	defer c.invalidate("/link")
+	return c.Client.CreateLocation(newLocation)
+}
+
+func (c *cachingClient) UpdateLocation(id string, location *Location) error {
+	defer c.invalidate("/link")
+	return c.Client.UpdateLocation(id, location)
+}
+
+func (c *cachingClient) DeleteLocation(id string, linkId string) error {
+	defer c.invalidate("/link")
+	return c.Client.DeleteLocation(id, linkId)
+}
+
+// GetRoute looks for the route in the cached location
+func (c *cachingClient) GetRoute(id string, linkId string, locationId string) (*LocationRoute, error) {
+	location, err := c.GetLocation(locationId, linkId)
+	if err != nil {
+		return nil, fmt.Errorf("GetRoute: Error on getting location: %s", err)
+	}
+
+	var route LocationRoute
+	for _, v := range location.Routes {
+		if v.ID == id {
+			route = v
+		}
+	}
+
+	return &route, nil
+}
+
+func (c *cachingClient) CreateRoute(newRoute LocationRoute) (*LocationRoute, error) {
+	defer c.invalidate("/link")
+	return c.Client.CreateRoute(newRoute)
+}
+
+func (c *cachingClient) UpdateRoute(id string, route *LocationRoute) error {
+	defer c.invalidate("/link")
+	return c.Client.UpdateRoute(id, route)
+}
+
+func (c *cachingClient) DeleteRoute(id string, linkId string, locationId string) error {
+	defer c.invalidate("/link")
+	return c.Client.DeleteRoute(id, linkId, locationId)
+}
+
+// GetHost looks for the host in the cached location
+func (c *cachingClient) GetHost(id string, linkId string, locationId string, uri any) (*LocationHost, error) {
+	location, err := c.GetLocation(locationId, linkId)
+	if err != nil {
+		return nil, fmt.Errorf("GetHost: Error on getting location: %s", err)
+	}
+
+	var host LocationHost
+	for _, v := range location.Hosts {
+		if v.ID == id {
+			host = v
+		}
+	}
+
+	if v, ok := uri.(string); ok && v != "" {
+		host.URI = v
+	} else {
+		host.URI, _ = c.GetHostURI(id, linkId, locationId)
+	}
+
+	return &host, nil
+}
+
+func (c *cachingClient) CreateHost(newHost LocationHost) (*LocationHost, error) {
+	defer c.invalidate("/link")
+	return c.Client.CreateHost(newHost)
+}
+
+func (c *cachingClient) UpdateHost(id string, host *LocationHost) error {
+	defer c.invalidate("/link")
+	return c.Client.UpdateHost(id, host)
+}
+
+func (c *cachingClient) DeleteHost(id string, linkId string, locationId string) error {
+	defer c.invalidate("/link")
+	return c.Client.DeleteHost(id, linkId, locationId)
+}
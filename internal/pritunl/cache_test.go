@@ -0,0 +1,236 @@
+package pritunl
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingClient counts the requests made to the list endpoints
+type countingClient struct {
+	Client
+
+	serversCalls   atomic.Int32
+	linksCalls     atomic.Int32
+	locationsCalls atomic.Int32
+
+	// serversGate blocks GetServers until it is closed when set
+	serversGate chan struct{}
+
+	usersCalls atomic.Int32
+	userName   atomic.Value
+	// usersGate blocks GetUser until it is closed when set
+	usersGate chan struct{}
+}
+
+func (c *countingClient) GetServers() ([]Server, error) {
+	c.serversCalls.Add(1)
+	if c.serversGate != nil {
+		<-c.serversGate
+	}
+	return []Server{{ID: "server1", Name: "server1"}}, nil
+}
+
+func (c *countingClient) UpdateServer(_ string, _ *Server) error {
+	return nil
+}
+
+func (c *countingClient) GetUser(id string, orgId string) (*User, error) {
+	c.usersCalls.Add(1)
+	user := &User{ID: id, Organization: orgId, Name: c.userName.Load().(string)}
+	if c.usersGate != nil {
+		<-c.usersGate
+	}
+	return user, nil
+}
+
+func (c *countingClient) UpdateUser(_ string, user *User) error {
+	c.userName.Store(user.Name)
+	return nil
+}
+
+func (c *countingClient) GetLinks() ([]Link, error) {
+	c.linksCalls.Add(1)
+	return []Link{{ID: "link1"}, {ID: "link2"}}, nil
+}
+
+func (c *countingClient) GetLocations(linkId string) ([]Location, error) {
+	c.locationsCalls.Add(1)
+	return []Location{
+		{
+			ID:     "location1",
+			LinkId: linkId,
+			Routes: []LocationRoute{{ID: "route1", Network: "10.0.0.0/24"}},
+			Hosts:  []LocationHost{{ID: "host1", Name: "host1"}},
+		},
+	}, nil
+}
+
+func (c *countingClient) CreateRoute(newRoute LocationRoute) (*LocationRoute, error) {
+	return &newRoute, nil
+}
+
+func TestCachingClientMemoizesLists(t *testing.T) {
+	underlying := &countingClient{}
+	apiClient := NewCachingClient(underlying)
+
+	for i := 0; i < 3; i++ {
+		servers, err := apiClient.GetServers()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(servers) != 1 {
+			t.Fatalf("expected 1 server, got %d", len(servers))
+		}
+	}
+
+	if calls := underlying.serversCalls.Load(); calls != 1 {
+		t.Fatalf("expected 1 request, got %d", calls)
+	}
+}
+
+func TestCachingClientInvalidatesOnWrite(t *testing.T) {
+	underlying := &countingClient{}
+	apiClient := NewCachingClient(underlying)
+
+	apiClient.GetServers()
+	if err := apiClient.UpdateServer("server1", &Server{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	apiClient.GetServers()
+
+	if calls := underlying.serversCalls.Load(); calls != 2 {
+		t.Fatalf("expected 2 requests, got %d", calls)
+	}
+
+	// writes to other collections keep the cached servers
+	apiClient.GetLocations("link1")
+	if _, err := apiClient.CreateRoute(LocationRoute{LinkId: "link1", LocationId: "location1"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	apiClient.GetServers()
+	apiClient.GetLocations("link1")
+
+	if calls := underlying.serversCalls.Load(); calls != 2 {
+		t.Fatalf("expected 2 requests of servers, got %d", calls)
+	}
+	if calls := underlying.locationsCalls.Load(); calls != 2 {
+		t.Fatalf("expected 2 requests of locations, got %d", calls)
+	}
+}
+
+func TestCachingClientCoalescesConcurrentRequests(t *testing.T) {
+	underlying := &countingClient{serversGate: make(chan struct{})}
+	apiClient := NewCachingClient(underlying)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := apiClient.GetServers(); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+
+	// let all the goroutines join the request in flight
+	time.Sleep(50 * time.Millisecond)
+	close(underlying.serversGate)
+	wg.Wait()
+
+	if calls := underlying.serversCalls.Load(); calls != 1 {
+		t.Fatalf("expected 1 request, got %d", calls)
+	}
+}
+
+func TestCachingClientDoesNotShareReadsStartedBeforeWrite(t *testing.T) {
+	underlying := &countingClient{usersGate: make(chan struct{})}
+	underlying.userName.Store("before")
+	apiClient := NewCachingClient(underlying)
+
+	read := func() <-chan *User {
+		result := make(chan *User, 1)
+		go func() {
+			user, err := apiClient.GetUser("user1", "org1")
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			result <- user
+		}()
+		return result
+	}
+
+	waitForCalls := func(calls int32) {
+		for deadline := time.Now().Add(time.Second); underlying.usersCalls.Load() < calls && time.Now().Before(deadline); {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	first := read()
+	waitForCalls(1)
+
+	if err := apiClient.UpdateUser("user1", &User{Name: "after"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	second := read()
+	waitForCalls(2)
+	close(underlying.usersGate)
+
+	if user := <-first; user == nil || user.Name != "before" {
+		t.Fatalf("expected the read started before the write to return the previous user, got %+v", user)
+	}
+	if user := <-second; user == nil || user.Name != "after" {
+		t.Fatalf("expected the read started after the write to return the updated user, got %+v", user)
+	}
+	if calls := underlying.usersCalls.Load(); calls != 2 {
+		t.Fatalf("expected 2 requests, got %d", calls)
+	}
+}
+
+func TestCachingClientReturnsCopies(t *testing.T) {
+	apiClient := NewCachingClient(&countingClient{})
+
+	servers, _ := apiClient.GetServers()
+	servers[0].Name = "changed"
+
+	servers, _ = apiClient.GetServers()
+	if servers[0].Name != "server1" {
+		t.Fatalf("expected the cached list not to be changed, got %s", servers[0].Name)
+	}
+}
+
+func TestCachingClientLinkObjectsUseCachedLists(t *testing.T) {
+	underlying := &countingClient{}
+	apiClient := NewCachingClient(underlying)
+
+	link, err := apiClient.GetLink("link2")
+	if err != nil || link.ID != "link2" {
+		t.Fatalf("expected link2, got %+v (%v)", link, err)
+	}
+	apiClient.GetLink("link1")
+
+	location, err := apiClient.GetLocation("location1", "link1")
+	if err != nil || location.ID != "location1" {
+		t.Fatalf("expected location1, got %+v (%v)", location, err)
+	}
+
+	route, err := apiClient.GetRoute("route1", "link1", "location1")
+	if err != nil || route.Network != "10.0.0.0/24" {
+		t.Fatalf("expected route1, got %+v (%v)", route, err)
+	}
+
+	host, err := apiClient.GetHost("host1", "link1", "location1", "pritunl://host1")
+	if err != nil || host.Name != "host1" || host.URI != "pritunl://host1" {
+		t.Fatalf("expected host1, got %+v (%v)", host, err)
+	}
+
+	if calls := underlying.linksCalls.Load(); calls != 1 {
+		t.Fatalf("expected 1 request of links, got %d", calls)
+	}
+	if calls := underlying.locationsCalls.Load(); calls != 1 {
+		t.Fatalf("expected 1 request of locations, got %d", calls)
+	}
+}
@@ -4,11 +4,17 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
 )
 
+// ErrNotFound is wrapped by the errors of the requests for an object which doesn't exist
+var ErrNotFound = errors.New("not found")
+
 type Client interface {
 	TestApiCall() error
 
@@ -22,6 +28,7 @@ type Client interface {
 	CreateUser(newUser User) (*User, error)
 	UpdateUser(id string, user *User) error
 	DeleteUser(id string, orgId string) error
+	GetUserAuditEvents(id string, orgId string) ([]AuditEvent, error)
 
 	GetServers() ([]Server, error)
 	GetServer(id string) (*Server, error)
@@ -40,12 +47,33 @@ type Client interface {
 	UpdateRouteOnServer(serverId string, route Route) error
 
 	GetHosts() ([]Host, error)
+	GetClusterHost(id string) (*Host, error)
+	GetHostUsage(hostId string, period string) (*HostUsage, error)
+	UpdateClusterHost(id string, host *Host) error
+	DeleteClusterHost(id string) error
 	GetHostsByServer(serverId string) ([]Host, error)
 	AttachHostToServer(hostId, serverId string) error
 	DetachHostFromServer(hostId, serverId string) error
 
+	GetServerLinks(serverId string) ([]ServerLink, error)
+	AttachServerLink(serverId, linkServerId string, useLocalAddress bool) error
+	DetachServerLink(serverId, linkServerId string) error
+
+	GetSettings() (map[string]interface{}, error)
+	UpdateSettings(settings map[string]interface{}) error
+
+	GetAdministrators() ([]Administrator, error)
+	GetAdministrator(id string) (*Administrator, error)
+	CreateAdministrator(newAdministrator Administrator) (*Administrator, error)
+	UpdateAdministrator(id string, administrator *Administrator) error
+	RegenerateAdministratorAPIKey(id string) (*Administrator, error)
+	DeleteAdministrator(id string) error
+
 	StartServer(serverId string) error
 	StopServer(serverId string) error
+	GetServerOutput(serverId string) ([]string, error)
+	GetServerBandwidth(serverId string, period string) (*ServerBandwidth, error)
+	GetServerClients(serverId string) ([]ServerClient, error)
 
 	GetLinks() ([]Link, error)
 	GetLink(id string) (*Link, error)
@@ -65,9 +93,12 @@ type Client interface {
 	DeleteRoute(id string, linkId string, locationId string) error
 
 	GetHost(id string, linkId string, locationId string, uri any) (*LocationHost, error)
+	GetHostURI(id string, linkId string, locationId string) (string, error)
 	CreateHost(newRoute LocationHost) (*LocationHost, error)
 	UpdateHost(id string, route *LocationHost) error
 	DeleteHost(id string, linkId string, locationId string) error
+
+	InvalidateCache(prefixes ...string)
 }
 
 type client struct {
@@ -98,6 +129,9 @@ func (c client) TestApiCall() error {
 	return nil
 }
 
+// InvalidateCache does nothing, the client doesn't cache the responses
+func (c client) InvalidateCache(prefixes ...string) {}
+
 func (c client) GetOrganization(id string) (*Organization, error) {
 	url := fmt.Sprintf("/organization/%s", id)
 	req, err := http.NewRequest("GET", url, nil)
@@ -124,25 +158,9 @@ func (c client) GetOrganization(id string) (*Organization, error) {
 }
 
 func (c client) GetOrganizations() ([]Organization, error) {
-	url := fmt.Sprintf("/organization")
-	req, err := http.NewRequest("GET", url, nil)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("GetOrganization: Error on HTTP request: %s", err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("Non-200 response on getting the organization\nbody=%s", body)
-	}
-
-	var organizations []Organization
-
-	err = json.Unmarshal(body, &organizations)
+	organizations, err := getAllPages[Organization](c, "/organization", "organizations")
 	if err != nil {
-		return nil, fmt.Errorf("GetOrganization: %s: %+v, body=%s", err, organizations, body)
+		return nil, fmt.Errorf("GetOrganizations: %s", err)
 	}
 
 	return organizations, nil
@@ -231,28 +249,12 @@ func (c client) GetLink(id string) (*Link, error) {
 }
 
 func (c client) GetLinks() ([]Link, error) {
-	url := fmt.Sprintf("/link")
-	req, err := http.NewRequest("GET", url, nil)
-
-	resp, err := c.httpClient.Do(req)
+	links, err := getAllPages[Link](c, "/link", "links")
 	if err != nil {
-		return nil, fmt.Errorf("GetLink: Error on HTTP request: %s", err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("Non-200 response on getting the links\nbody=%s", body)
+		return nil, fmt.Errorf("GetLinks: %s", err)
 	}
 
-	var links Links
-
-	err = json.Unmarshal(body, &links)
-	if err != nil {
-		return nil, fmt.Errorf("GetLinks: %s: %+v, body=%s", err, links, body)
-	}
-
-	return links.Links, nil
+	return links, nil
 }
 
 func (c client) CreateLink(newLink Link) (*Link, error) {
@@ -351,25 +353,9 @@ func (c client) GetServer(id string) (*Server, error) {
 }
 
 func (c client) GetServers() ([]Server, error) {
-	url := fmt.Sprintf("/server")
-	req, err := http.NewRequest("GET", url, nil)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("GetServers: Error on HTTP request: %s", err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("Non-200 response on getting servers\nbody=%s", body)
-	}
-
-	var servers []Server
-	err = json.Unmarshal(body, &servers)
-
+	servers, err := getAllPages[Server](c, "/server", "servers")
 	if err != nil {
-		return nil, fmt.Errorf("GetServers: %s: %+v, body=%s", err, servers, body)
+		return nil, fmt.Errorf("GetServers: %s", err)
 	}
 
 	return servers, nil
@@ -610,22 +596,10 @@ func (c client) DeleteServer(id string) error {
 }
 
 func (c client) GetOrganizationsByServer(serverId string) ([]Organization, error) {
-	url := fmt.Sprintf("/server/%s/organization", serverId)
-	req, err := http.NewRequest("GET", url, nil)
-
-	resp, err := c.httpClient.Do(req)
+	organizations, err := getAllPages[Organization](c, fmt.Sprintf("/server/%s/organization", serverId), "organizations")
 	if err != nil {
-		return nil, fmt.Errorf("GeteOrganizationsByServer: Error on HTTP request: %s", err)
+		return nil, fmt.Errorf("GetOrganizationsByServer: %s", err)
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("Non-200 response on getting organizations on the server\nbody=%s", body)
-	}
-
-	var organizations []Organization
-	json.Unmarshal(body, &organizations)
 
 	return organizations, nil
 }
@@ -702,23 +676,101 @@ func (c client) StopServer(serverId string) error {
 	return nil
 }
 
-func (c client) GetRoutesByServer(serverId string) ([]Route, error) {
-	url := fmt.Sprintf("/server/%s/route", serverId)
+// GetServerOutput returns the recent output lines of the server processes, the oldest first
+func (c client) GetServerOutput(serverId string) ([]string, error) {
+	url := fmt.Sprintf("/server/%s/output", serverId)
 	req, err := http.NewRequest("GET", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("GetRoutesByServer: Error on HTTP request: %s", err)
+		return nil, fmt.Errorf("GetServerOutput: Error on HTTP request: %s", err)
 	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("Non-200 response on getting routes on the server\nbody=%s", body)
+		return nil, fmt.Errorf("Non-200 response on getting the server output\ncode=%d\nbody=%s", resp.StatusCode, body)
 	}
 
-	var routes []Route
-	json.Unmarshal(body, &routes)
+	var output struct {
+		Output []string `json:"output"`
+	}
+	err = json.Unmarshal(body, &output)
+	if err != nil {
+		return nil, fmt.Errorf("GetServerOutput: %s, serverId=%s, body=%s", err, serverId, body)
+	}
+
+	return output.Output, nil
+}
+
+func (c client) GetServerBandwidth(serverId string, period string) (*ServerBandwidth, error) {
+	if !slices.Contains(MetricPeriods, period) {
+		return nil, fmt.Errorf("GetServerBandwidth: invalid period %s, expected one of %v", period, MetricPeriods)
+	}
+
+	url := fmt.Sprintf("/server/%s/bandwidth/%s", serverId, period)
+	req, err := http.NewRequest("GET", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetServerBandwidth: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the server bandwidth\ncode=%d\nbody=%s", resp.StatusCode, body)
+	}
+
+	var bandwidth ServerBandwidth
+	err = json.Unmarshal(body, &bandwidth)
+	if err != nil {
+		return nil, fmt.Errorf("GetServerBandwidth: %s, serverId=%s, body=%s", err, serverId, body)
+	}
+
+	return &bandwidth, nil
+}
+
+// GetServerClients returns the clients connected to the server. Pritunl has no endpoint listing them,
+// so they are collected from the users of the organizations attached to the server.
+func (c client) GetServerClients(serverId string) ([]ServerClient, error) {
+	organizations, err := c.GetOrganizationsByServer(serverId)
+	if err != nil {
+		return nil, fmt.Errorf("GetServerClients: %s", err)
+	}
+
+	clients := make([]ServerClient, 0)
+	for _, organization := range organizations {
+		users, err := getAllPages[User](c, fmt.Sprintf("/user/%s", organization.ID), "users")
+		if err != nil {
+			return nil, fmt.Errorf("GetServerClients: %s", err)
+		}
+
+		for _, user := range users {
+			for _, server := range user.Servers {
+				if server.ServerID != serverId || !server.Status {
+					continue
+				}
+
+				clients = append(clients, ServerClient{
+					UserServer:       server,
+					UserID:           user.ID,
+					UserName:         user.Name,
+					OrganizationID:   organization.ID,
+					OrganizationName: organization.Name,
+				})
+			}
+		}
+	}
+
+	return clients, nil
+}
+
+func (c client) GetRoutesByServer(serverId string) ([]Route, error) {
+	routes, err := getAllPages[Route](c, fmt.Sprintf("/server/%s/route", serverId), "routes")
+	if err != nil {
+		return nil, fmt.Errorf("GetRoutesByServer: %s", err)
+	}
 
 	return routes, nil
 }
@@ -825,6 +877,30 @@ func (c client) GetUser(id string, orgId string) (*User, error) {
 	return &user, nil
 }
 
+func (c client) GetUserAuditEvents(id string, orgId string) ([]AuditEvent, error) {
+	url := fmt.Sprintf("/user/%s/%s/audit", orgId, id)
+	req, err := http.NewRequest("GET", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetUserAuditEvents: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the user audit events\ncode=%d\nbody=%s", resp.StatusCode, body)
+	}
+
+	var events []AuditEvent
+	err = json.Unmarshal(body, &events)
+	if err != nil {
+		return nil, fmt.Errorf("GetUserAuditEvents: %s, id=%s, body=%s", err, id, body)
+	}
+
+	return events, nil
+}
+
 func (c client) CreateUser(newUser User) (*User, error) {
 	jsonData, err := json.Marshal(newUser)
 	if err != nil {
@@ -900,50 +976,117 @@ func (c client) DeleteUser(id string, orgId string) error {
 }
 
 func (c client) GetHosts() ([]Host, error) {
-	url := fmt.Sprintf("/host")
+	hosts, err := getAllPages[Host](c, "/host", "hosts")
+	if err != nil {
+		return nil, fmt.Errorf("GetHosts: %s", err)
+	}
+
+	return hosts, nil
+}
+
+func (c client) GetClusterHost(id string) (*Host, error) {
+	url := fmt.Sprintf("/host/%s", id)
 	req, err := http.NewRequest("GET", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("GetHosts: Error on HTTP request: %s", err)
+		return nil, fmt.Errorf("GetClusterHost: Error on HTTP request: %s", err)
 	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("GetClusterHost: host %s: %w", id, ErrNotFound)
+	}
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("Non-200 response on getting the hosts\nbody=%s", body)
+		return nil, fmt.Errorf("Non-200 response on getting the host\ncode=%d\nbody=%s", resp.StatusCode, body)
 	}
 
-	var hosts []Host
-
-	err = json.Unmarshal(body, &hosts)
+	var host Host
+	err = json.Unmarshal(body, &host)
 	if err != nil {
-		return nil, fmt.Errorf("GetHosts: %s: %+v, body=%s", err, hosts, body)
+		return nil, fmt.Errorf("GetClusterHost: %s: %+v, id=%s, body=%s", err, host, id, body)
 	}
 
-	return hosts, nil
+	return &host, nil
 }
 
-func (c client) GetHostsByServer(serverId string) ([]Host, error) {
-	url := fmt.Sprintf("/server/%s/host", serverId)
+func (c client) GetHostUsage(hostId string, period string) (*HostUsage, error) {
+	if !slices.Contains(MetricPeriods, period) {
+		return nil, fmt.Errorf("GetHostUsage: invalid period %s, expected one of %v", period, MetricPeriods)
+	}
+
+	url := fmt.Sprintf("/host/%s/usage/%s", hostId, period)
 	req, err := http.NewRequest("GET", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("GetHostsByServer: Error on HTTP request: %s", err)
+		return nil, fmt.Errorf("GetHostUsage: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the host usage\ncode=%d\nbody=%s", resp.StatusCode, body)
+	}
+
+	var usage HostUsage
+	err = json.Unmarshal(body, &usage)
+	if err != nil {
+		return nil, fmt.Errorf("GetHostUsage: %s, hostId=%s, body=%s", err, hostId, body)
+	}
+
+	return &usage, nil
+}
+
+func (c client) UpdateClusterHost(id string, host *Host) error {
+	jsonData, err := json.Marshal(host)
+	if err != nil {
+		return fmt.Errorf("UpdateClusterHost: Error on marshalling data: %s", err)
+	}
+
+	url := fmt.Sprintf("/host/%s", id)
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("UpdateClusterHost: Error on HTTP request: %s", err)
 	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("Non-200 response on getting hosts by the server\nbody=%s", body)
+		return fmt.Errorf("Non-200 response on updating the host\nbody=%s", body)
 	}
 
-	var hosts []Host
+	return nil
+}
+
+func (c client) DeleteClusterHost(id string) error {
+	url := fmt.Sprintf("/host/%s", id)
+	req, err := http.NewRequest("DELETE", url, nil)
 
-	err = json.Unmarshal(body, &hosts)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("GetHostsByServer: %s: %+v, body=%s", err, hosts, body)
+		return fmt.Errorf("DeleteClusterHost: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("DeleteClusterHost: host %s: %w", id, ErrNotFound)
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Non-200 response on deleting the host\nbody=%s", body)
+	}
+
+	return nil
+}
+
+func (c client) GetHostsByServer(serverId string) ([]Host, error) {
+	hosts, err := getAllPages[Host](c, fmt.Sprintf("/server/%s/host", serverId), "hosts")
+	if err != nil {
+		return nil, fmt.Errorf("GetHostsByServer: %s", err)
 	}
 
 	return hosts, nil
@@ -985,27 +1128,63 @@ func (c client) DetachHostFromServer(hostId, serverId string) error {
 	return nil
 }
 
-// GetLocations Locations
-func (c client) GetLocations(linkId string) ([]Location, error) {
-	url := fmt.Sprintf("/link/%s/location", linkId)
-	req, err := http.NewRequest("GET", url, nil)
+func (c client) GetServerLinks(serverId string) ([]ServerLink, error) {
+	links, err := getAllPages[ServerLink](c, fmt.Sprintf("/server/%s/link", serverId), "links")
+	if err != nil {
+		return nil, fmt.Errorf("GetServerLinks: %s", err)
+	}
+
+	return links, nil
+}
+
+// AttachServerLink links the servers or changes the link, both servers must be offline
+func (c client) AttachServerLink(serverId, linkServerId string, useLocalAddress bool) error {
+	jsonData, err := json.Marshal(ServerLink{UseLocalAddress: useLocalAddress})
+	if err != nil {
+		return fmt.Errorf("AttachServerLink: Error on marshalling data: %s", err)
+	}
+
+	url := fmt.Sprintf("/server/%s/link/%s", serverId, linkServerId)
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("AttachServerLink: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Non-200 response on linking the servers\nbody=%s", body)
+	}
+
+	return nil
+}
+
+// DetachServerLink unlinks the servers, both servers must be offline
+func (c client) DetachServerLink(serverId, linkServerId string) error {
+	url := fmt.Sprintf("/server/%s/link/%s", serverId, linkServerId)
+	req, err := http.NewRequest("DELETE", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("GetLocations: Error on HTTP request: %s", err)
+		return fmt.Errorf("DetachServerLink: Error on HTTP request: %s", err)
 	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("Non-200 response on getting the locations\nbody=%s", body)
+		return fmt.Errorf("Non-200 response on unlinking the servers\nbody=%s", body)
 	}
 
-	var locations []Location
+	return nil
+}
 
-	err = json.Unmarshal(body, &locations)
+// GetLocations Locations
+func (c client) GetLocations(linkId string) ([]Location, error) {
+	locations, err := getAllPages[Location](c, fmt.Sprintf("/link/%s/location", linkId), "locations")
 	if err != nil {
-		return nil, fmt.Errorf("GetLocations: %s: %+v, body=%s", err, locations, body)
+		return nil, fmt.Errorf("GetLocations: %s", err)
 	}
 
 	return locations, nil
@@ -1019,25 +1198,9 @@ func (c client) GetLocation(id string, linkId string) (*Location, error) {
 		return nil, fmt.Errorf("GetLocation: Error on getting link: %s, %+v", err, link)
 	}
 
-	url := fmt.Sprintf("/link/%s/location", linkId)
-	req, err := http.NewRequest("GET", url, nil)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("GetLocation: Error on HTTP request: %s", err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("Non-200 response on getting the locations\nbody=%s", body)
-	}
-
-	var locations []Location
-
-	err = json.Unmarshal(body, &locations)
+	locations, err := c.GetLocations(linkId)
 	if err != nil {
-		return nil, fmt.Errorf("GetLocation: %s: %+v, body=%s", err, locations, body)
+		return nil, fmt.Errorf("GetLocation: %s", err)
 	}
 
 	var location Location
@@ -1327,18 +1490,257 @@ func (c client) DeleteHost(id string, linkId string, locationId string) error {
 	return nil
 }
 
-func NewClient(baseUrl, apiToken, apiSecret string, insecure bool) Client {
+func (c client) GetSettings() (map[string]interface{}, error) {
+	url := "/settings"
+	req, err := http.NewRequest("GET", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetSettings: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the settings\ncode=%d\nbody=%s", resp.StatusCode, body)
+	}
+
+	var settings map[string]interface{}
+	err = json.Unmarshal(body, &settings)
+	if err != nil {
+		return nil, fmt.Errorf("GetSettings: %s, body=%s", err, body)
+	}
+
+	return settings, nil
+}
+
+// UpdateSettings changes only the settings in the map, the other settings are kept
+func (c client) UpdateSettings(settings map[string]interface{}) error {
+	jsonData, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("UpdateSettings: Error on marshalling data: %s", err)
+	}
+
+	url := "/settings"
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("UpdateSettings: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Non-200 response on updating the settings\nbody=%s", body)
+	}
+
+	return nil
+}
+
+func (c client) GetAdministrators() ([]Administrator, error) {
+	administrators, err := getAllPages[Administrator](c, "/admin", "administrators")
+	if err != nil {
+		return nil, fmt.Errorf("GetAdministrators: %s", err)
+	}
+
+	return administrators, nil
+}
+
+func (c client) GetAdministrator(id string) (*Administrator, error) {
+	url := fmt.Sprintf("/admin/%s", id)
+	req, err := http.NewRequest("GET", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetAdministrator: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the administrator\ncode=%d\nbody=%s", resp.StatusCode, body)
+	}
+
+	var administrator Administrator
+	err = json.Unmarshal(body, &administrator)
+	if err != nil {
+		return nil, fmt.Errorf("GetAdministrator: %s: id=%s", err, id)
+	}
+
+	return &administrator, nil
+}
+
+func (c client) CreateAdministrator(newAdministrator Administrator) (*Administrator, error) {
+	jsonData, err := json.Marshal(newAdministrator)
+	if err != nil {
+		return nil, fmt.Errorf("CreateAdministrator: Error on marshalling data: %s", err)
+	}
+
+	url := "/admin"
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("CreateAdministrator: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on creating the administrator\nbody=%s", body)
+	}
+
+	var administrator Administrator
+	err = json.Unmarshal(body, &administrator)
+	if err != nil {
+		return nil, fmt.Errorf("CreateAdministrator: %s: username=%s", err, newAdministrator.Username)
+	}
+
+	return &administrator, nil
+}
+
+// UpdateAdministrator never sends the token, secret and OTP secret, Pritunl regenerates them when they are set in the request
+func (c client) UpdateAdministrator(id string, administrator *Administrator) error {
+	update := *administrator
+	update.Token = ""
+	update.Secret = ""
+	update.OtpSecret = ""
+
+	jsonData, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("UpdateAdministrator: Error on marshalling data: %s", err)
+	}
+
+	url := fmt.Sprintf("/admin/%s", id)
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("UpdateAdministrator: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Non-200 response on updating the administrator\nbody=%s", body)
+	}
+
+	return nil
+}
+
+// RegenerateAdministratorAPIKey generates a new API token and secret for the administrator, the previous ones stop working
+func (c client) RegenerateAdministratorAPIKey(id string) (*Administrator, error) {
+	var jsonStr = []byte(`{"token": true, "secret": true}`)
+
+	url := fmt.Sprintf("/admin/%s", id)
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonStr))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("RegenerateAdministratorAPIKey: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on regenerating the API key of the administrator\nbody=%s", body)
+	}
+
+	var administrator Administrator
+	err = json.Unmarshal(body, &administrator)
+	if err != nil {
+		return nil, fmt.Errorf("RegenerateAdministratorAPIKey: %s: id=%s", err, id)
+	}
+
+	return &administrator, nil
+}
+
+func (c client) DeleteAdministrator(id string) error {
+	url := fmt.Sprintf("/admin/%s", id)
+	req, err := http.NewRequest("DELETE", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("DeleteAdministrator: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Non-200 response on deleting the administrator\nbody=%s", body)
+	}
+
+	return nil
+}
+
+// ClientOption configures the optional behaviour of the client
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	maxConcurrentRequests int
+	maxRetries            int
+	tlsConfig             *tls.Config
+	proxy                 func(*http.Request) (*url.URL, error)
+	username              string
+	password              string
+}
+
+// WithMaxConcurrentRequests limits the number of requests sent to the API at the same time, 0 means no limit
+func WithMaxConcurrentRequests(limit int) ClientOption {
+	return func(o *clientOptions) {
+		o.maxConcurrentRequests = limit
+	}
+}
+
+// WithMaxRetries sets how many times requests rejected with 429 or 503 status codes are retried
+func WithMaxRetries(retries int) ClientOption {
+	return func(o *clientOptions) {
+		o.maxRetries = retries
+	}
+}
+
+func NewClient(baseUrl, apiToken, apiSecret string, insecure bool, opts ...ClientOption) Client {
+	options := clientOptions{
+		maxRetries: defaultMaxRetries,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	tlsConfig := options.tlsConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{InsecureSkipVerify: insecure}
+	}
+
+	proxy := options.proxy
+	if proxy == nil {
+		proxy = http.ProxyFromEnvironment
+	}
+
 	underlyingTransport := &http.Transport{
-		Proxy:           http.ProxyFromEnvironment,
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+		Proxy:           proxy,
+		TLSClientConfig: tlsConfig,
 	}
+
+	// every attempt is signed separately, since the signature includes a timestamp and a nonce
+	var roundTripper http.RoundTripper = &transport{
+		baseUrl:             baseUrl,
+		apiToken:            apiToken,
+		apiSecret:           apiSecret,
+		underlyingTransport: underlyingTransport,
+	}
+	if options.username != "" {
+		roundTripper = newSessionTransport(underlyingTransport, baseUrl, options.username, options.password)
+	}
+	roundTripper = newLimitTransport(roundTripper, options.maxConcurrentRequests)
+	roundTripper = &retryTransport{
+		underlyingTransport: roundTripper,
+		maxRetries:          options.maxRetries,
+	}
+
 	httpClient := &http.Client{
-		Transport: &transport{
-			baseUrl:             baseUrl,
-			apiToken:            apiToken,
-			apiSecret:           apiSecret,
-			underlyingTransport: underlyingTransport,
-		},
+		Transport: roundTripper,
 	}
 
 	return &client{httpClient: httpClient}
@@ -1,5 +1,12 @@
 package pritunl
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Host is a node of the Pritunl cluster. The *_addr fields are the addresses in use, detected automatically
+// unless the *_address fields override them.
 type Host struct {
 	ID                string `json:"id,omitempty"`
 	Name              string `json:"name"`
@@ -14,4 +21,43 @@ type Host struct {
 	LinkAddr          string `json:"link_addr"`
 	SyncAddress       string `json:"sync_address"`
 	Status            string `json:"status"`
+
+	PublicAddress  string `json:"public_address"`
+	PublicAddress6 string `json:"public_address6"`
+	LocalAddress   string `json:"local_address"`
+	LocalAddress6  string `json:"local_address6"`
+	LinkAddress    string `json:"link_address"`
+}
+
+// UsagePoint is a sample of a usage series, the value is a percentage
+type UsagePoint struct {
+	Timestamp int64
+	Value     float64
+}
+
+// UnmarshalJSON decodes the [timestamp, value] pairs Pritunl returns
+func (p *UsagePoint) UnmarshalJSON(data []byte) error {
+	var pair []float64
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("expected a [timestamp, value] pair, got %s", data)
+	}
+
+	p.Timestamp = int64(pair[0])
+	p.Value = pair[1]
+
+	return nil
+}
+
+// MarshalJSON encodes the point as a [timestamp, value] pair
+func (p UsagePoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]float64{float64(p.Timestamp), p.Value})
+}
+
+// HostUsage is the CPU and memory usage of a host over a period
+type HostUsage struct {
+	CPU    []UsagePoint `json:"cpu"`
+	Memory []UsagePoint `json:"mem"`
 }
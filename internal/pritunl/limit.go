@@ -0,0 +1,40 @@
+package pritunl
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// limitTransport limits the number of requests sent to the API at the same time
+type limitTransport struct {
+	underlyingTransport http.RoundTripper
+	slots               chan struct{}
+}
+
+// newLimitTransport returns the underlying transport as is when there is no limit
+func newLimitTransport(underlyingTransport http.RoundTripper, limit int) http.RoundTripper {
+	if limit <= 0 {
+		return underlyingTransport
+	}
+
+	return &limitTransport{
+		underlyingTransport: underlyingTransport,
+		slots:               make(chan struct{}, limit),
+	}
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	queuedAt := time.Now()
+
+	select {
+	case t.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	defer func() { <-t.slots }()
+
+	log.Printf("[DEBUG] pritunl: %s %s was queued for %s, %d of %d requests in flight", req.Method, req.URL.Path, time.Since(queuedAt), len(t.slots), cap(t.slots))
+
+	return t.underlyingTransport.RoundTrip(req)
+}
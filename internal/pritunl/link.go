@@ -11,9 +11,3 @@ type Link struct {
 	IPv6           bool   `json:"ipv6"`
 	ForcePreferred bool   `json:"force_preferred"`
 }
-
-type Links struct {
-	Page      int    `json:"page"`
-	PageTotal int    `json:"page_total"`
-	Links     []Link `json:"links"`
-}
\ No newline at end of file
@@ -0,0 +1,28 @@
+package pritunl
+
+import (
+	"net"
+)
+
+// NormalizeNetwork returns the canonical form of a network address with a subnet,
+// the way Pritunl stores it: host bits are cleared and IPv6 addresses are compressed,
+// e.g. 10.0.0.1/24 becomes 10.0.0.0/24 and FD00:0::1/64 becomes fd00::/64.
+// Values which can't be parsed as a CIDR are returned unchanged.
+func NormalizeNetwork(network string) string {
+	_, ipNet, err := net.ParseCIDR(network)
+	if err != nil {
+		return network
+	}
+
+	return ipNet.String()
+}
+
+// NetworkHasHostBits checks if a network address with a subnet has any host bits set, e.g. 10.0.0.1/24
+func NetworkHasHostBits(network string) bool {
+	ip, ipNet, err := net.ParseCIDR(network)
+	if err != nil {
+		return false
+	}
+
+	return !ip.Equal(ipNet.IP)
+}
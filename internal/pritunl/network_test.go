@@ -0,0 +1,59 @@
+package pritunl
+
+import (
+	"testing"
+)
+
+func TestNormalizeNetwork(t *testing.T) {
+	testCases := map[string]string{
+		"10.0.0.0/24":         "10.0.0.0/24",
+		"10.0.0.1/24":         "10.0.0.0/24",
+		"192.168.17.200/20":   "192.168.16.0/20",
+		"10.4.0.0/32":         "10.4.0.0/32",
+		"0.0.0.0/0":           "0.0.0.0/0",
+		"fd00::/64":           "fd00::/64",
+		"FD00:0:0:0::1/64":    "fd00::/64",
+		"2001:db8:0:1::42/48": "2001:db8::/48",
+		"not-a-network":       "not-a-network",
+		"10.100.0.2":          "10.100.0.2",
+	}
+
+	for network, expected := range testCases {
+		if actual := NormalizeNetwork(network); actual != expected {
+			t.Errorf("NormalizeNetwork(%q) = %q, expected %q", network, actual, expected)
+		}
+	}
+}
+
+func TestNetworkHasHostBits(t *testing.T) {
+	testCases := map[string]bool{
+		"10.0.0.0/24":   false,
+		"10.0.0.1/24":   true,
+		"fd00::/64":     false,
+		"fd00::1/64":    true,
+		"not-a-network": false,
+	}
+
+	for network, expected := range testCases {
+		if actual := NetworkHasHostBits(network); actual != expected {
+			t.Errorf("NetworkHasHostBits(%q) = %v, expected %v", network, actual, expected)
+		}
+	}
+}
+
+func TestRouteGetID(t *testing.T) {
+	canonical := Route{Network: "10.0.0.0/24"}
+	withHostBits := Route{Network: "10.0.0.1/24"}
+
+	if canonical.GetID() != "31302e302e302e302f3234" {
+		t.Errorf("unexpected route ID %s", canonical.GetID())
+	}
+
+	if canonical.GetID() != withHostBits.GetID() {
+		t.Errorf("expected the same ID for %s and %s, got %s and %s", canonical.Network, withHostBits.Network, canonical.GetID(), withHostBits.GetID())
+	}
+
+	if (Route{}).GetID() != "" {
+		t.Errorf("expected an empty ID for a route without network")
+	}
+}
@@ -0,0 +1,106 @@
+package pritunl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// maxPages protects from endless loops when the API keeps reporting more pages
+const maxPages = 10000
+
+// listPage is a page of a paginated list, the items are stored under an endpoint specific key
+type listPage struct {
+	Page      int `json:"page"`
+	PageTotal int `json:"page_total"`
+	items     json.RawMessage
+}
+
+// getAllPages requests the list endpoint page by page until page_total is reached and returns the items of all the pages.
+// page_total is the index of the last page. Endpoints which return plain arrays are handled as a single page.
+func getAllPages[T any](c client, path string, itemsKey string) ([]T, error) {
+	result := make([]T, 0)
+
+	for page := 0; page < maxPages; page++ {
+		body, err := c.getPage(path, page)
+		if err != nil {
+			return nil, err
+		}
+
+		trimmed := bytes.TrimSpace(body)
+		if len(trimmed) > 0 && trimmed[0] == '[' {
+			var items []T
+			if err := json.Unmarshal(trimmed, &items); err != nil {
+				return nil, fmt.Errorf("%s, body=%s", err, body)
+			}
+			return append(result, items...), nil
+		}
+
+		current, err := decodeListPage(trimmed, itemsKey)
+		if err != nil {
+			return nil, fmt.Errorf("%s, body=%s", err, body)
+		}
+
+		var items []T
+		if len(current.items) > 0 {
+			if err := json.Unmarshal(current.items, &items); err != nil {
+				return nil, fmt.Errorf("%s, body=%s", err, body)
+			}
+		}
+		result = append(result, items...)
+
+		if current.Page >= current.PageTotal || len(items) == 0 {
+			return result, nil
+		}
+	}
+
+	return nil, fmt.Errorf("more than %d pages returned by %s", maxPages, path)
+}
+
+func decodeListPage(body []byte, itemsKey string) (*listPage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+
+	page := &listPage{items: fields[itemsKey]}
+	if v, ok := fields["page"]; ok {
+		if err := json.Unmarshal(v, &page.Page); err != nil {
+			return nil, fmt.Errorf("invalid page: %s", err)
+		}
+	}
+	if v, ok := fields["page_total"]; ok {
+		if err := json.Unmarshal(v, &page.PageTotal); err != nil {
+			return nil, fmt.Errorf("invalid page_total: %s", err)
+		}
+	}
+
+	return page, nil
+}
+
+func (c client) getPage(path string, page int) ([]byte, error) {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+
+	req, err := http.NewRequest("GET", path+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting page %d of %s\ncode=%d\nbody=%s", page, path, resp.StatusCode, body)
+	}
+
+	return body, nil
+}
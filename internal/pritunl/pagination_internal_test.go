@@ -0,0 +1,83 @@
+package pritunl
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestGetAllPagesHandlesResponses(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pages    []string
+		expected []string
+		requests int
+	}{
+		{
+			name:     "plain array",
+			pages:    []string{`[{"id": "a"}, {"id": "b"}]`},
+			expected: []string{"a", "b"},
+			requests: 1,
+		},
+		{
+			name: "page_total is the index of the last page",
+			pages: []string{
+				`{"page": 0, "page_total": 1, "items": [{"id": "a"}]}`,
+				`{"page": 1, "page_total": 1, "items": [{"id": "b"}]}`,
+			},
+			expected: []string{"a", "b"},
+			requests: 2,
+		},
+		{
+			name:     "missing items",
+			pages:    []string{`{"page": 0, "page_total": 0}`},
+			expected: []string{},
+			requests: 1,
+		},
+		{
+			name: "stops on an empty page",
+			pages: []string{
+				`{"page": 0, "page_total": 5, "items": [{"id": "a"}]}`,
+				`{"page": 1, "page_total": 5, "items": []}`,
+			},
+			expected: []string{"a"},
+			requests: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+				if page >= len(testCase.pages) {
+					http.Error(w, "no such page", http.StatusNotFound)
+					return
+				}
+				fmt.Fprint(w, testCase.pages[page])
+			}))
+			defer server.Close()
+
+			apiClient := NewClient(server.URL, "token", "secret", false).(*client)
+
+			items, err := getAllPages[Organization](*apiClient, "/organization", "items")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			ids := make([]string, 0)
+			for _, item := range items {
+				ids = append(ids, item.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(testCase.expected) {
+				t.Fatalf("expected %v, got %v", testCase.expected, ids)
+			}
+			if requests != testCase.requests {
+				t.Fatalf("expected %d requests, got %d", testCase.requests, requests)
+			}
+		})
+	}
+}
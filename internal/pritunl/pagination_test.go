@@ -0,0 +1,88 @@
+package pritunl_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestListsFollowAllPages(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+	fake.SetPageSize(2)
+
+	for i := 0; i < 5; i++ {
+		fake.AddOrganization(fmt.Sprintf("org%d", i))
+		fake.AddServer(pritunl.Server{Name: fmt.Sprintf("server%d", i)})
+		fake.AddHost(pritunl.Host{Name: fmt.Sprintf("host%d", i)})
+		fake.AddLink(pritunl.Link{Name: fmt.Sprintf("link%d", i)})
+	}
+
+	apiClient := fake.NewClient()
+
+	organizations, err := apiClient.GetOrganizations()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(organizations) != 5 {
+		t.Fatalf("expected 5 organizations, got %d", len(organizations))
+	}
+
+	servers, err := apiClient.GetServers()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(servers) != 5 || servers[4].Name != "server4" {
+		t.Fatalf("expected 5 servers, got %+v", servers)
+	}
+
+	hosts, err := apiClient.GetHosts()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(hosts) != 5 {
+		t.Fatalf("expected 5 hosts, got %d", len(hosts))
+	}
+
+	links, err := apiClient.GetLinks()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(links) != 5 {
+		t.Fatalf("expected 5 links, got %d", len(links))
+	}
+
+	link, err := apiClient.GetLink(links[4].ID)
+	if err != nil || link.Name != "link4" {
+		t.Fatalf("expected the link from the last page, got %+v (%v)", link, err)
+	}
+
+	// 3 pages of 2 servers
+	if requests := fake.Requests("GET /server"); requests != 3 {
+		t.Fatalf("expected 3 requests of servers, got %d", requests)
+	}
+}
+
+func TestListsWithoutPagination(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	fake.AddServer(pritunl.Server{Name: "server1"})
+	fake.AddLink(pritunl.Link{Name: "link1"})
+
+	apiClient := fake.NewClient()
+
+	servers, err := apiClient.GetServers()
+	if err != nil || len(servers) != 1 {
+		t.Fatalf("expected 1 server, got %+v (%v)", servers, err)
+	}
+
+	links, err := apiClient.GetLinks()
+	if err != nil || len(links) != 1 {
+		t.Fatalf("expected 1 link, got %+v (%v)", links, err)
+	}
+
+	if requests := fake.Requests("GET /server"); requests != 1 {
+		t.Fatalf("expected a single request of servers, got %d", requests)
+	}
+}
@@ -0,0 +1,126 @@
+package pritunltest
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+// Credentials accepted by the fake server
+const (
+	Token    = "token"
+	Secret   = "secret"
+	Username = "pritunl"
+	Password = "pritunl"
+)
+
+const (
+	sessionCookie = "session"
+	// authTimeWindow is the difference between the signature timestamp and the server time which is accepted
+	authTimeWindow = 5 * time.Minute
+)
+
+// NewSessionClient returns a client of the Pritunl API pointed to the fake server, authenticated with an administrator session
+func (s *Server) NewSessionClient() pritunl.Client {
+	return pritunl.NewClient(s.URL, "", "", false, pritunl.WithSessionAuth(Username, Password))
+}
+
+// ExpireSessions logs out all the administrator sessions
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions = make(map[string]string)
+}
+
+// authenticate checks the signature of the API token or the session cookie with its CSRF token
+func (s *Server) authenticate(pattern string, r *http.Request) bool {
+	if r.Header.Get("Auth-Token") != "" {
+		return validSignature(r)
+	}
+
+	csrf, ok := s.sessionCSRF(r)
+
+	// the CSRF token is fetched from the state, the other requests must send it
+	return ok && (pattern == "GET /state" || r.Header.Get("Csrf-Token") == csrf)
+}
+
+// sessionCSRF returns the CSRF token of the session of the request
+func (s *Server) sessionCSRF(r *http.Request) (string, bool) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return "", false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	csrf, ok := s.sessions[cookie.Value]
+	return csrf, ok
+}
+
+func validSignature(r *http.Request) bool {
+	timestamp, err := strconv.ParseInt(r.Header.Get("Auth-Timestamp"), 10, 64)
+	if err != nil {
+		return false
+	}
+	difference := time.Since(time.Unix(timestamp, 0))
+	if difference > authTimeWindow || difference < -authTimeWindow {
+		return false
+	}
+
+	authString := strings.Join([]string{
+		r.Header.Get("Auth-Token"),
+		r.Header.Get("Auth-Timestamp"),
+		r.Header.Get("Auth-Nonce"),
+		r.Method,
+		r.URL.Path,
+	}, "&")
+
+	mac := hmac.New(sha256.New, []byte(Secret))
+	mac.Write([]byte(authString))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	return r.Header.Get("Auth-Token") == Token && hmac.Equal([]byte(r.Header.Get("Auth-Signature")), []byte(expected))
+}
+
+func (s *Server) createSession(w http.ResponseWriter, r *http.Request) {
+	var credentials struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if credentials.Username != Username || credentials.Password != Password {
+		writeError(w, http.StatusUnauthorized, "Username or password is not valid.")
+		return
+	}
+
+	session, csrf := randomToken(), randomToken()
+
+	s.mu.Lock()
+	s.sessions[session] = csrf
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: session, Path: "/", HttpOnly: true})
+	writeJSON(w, map[string]interface{}{"authenticated": true})
+}
+
+func randomToken() string {
+	token := make([]byte, 16)
+	rand.Read(token)
+
+	return hex.EncodeToString(token)
+}
@@ -0,0 +1,833 @@
+package pritunltest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+func (s *Server) getState(w http.ResponseWriter, r *http.Request) {
+	state := map[string]interface{}{}
+	if csrf, ok := s.sessionCSRF(r); ok {
+		state["csrf_token"] = csrf
+	}
+
+	writeJSON(w, state)
+}
+
+func (s *Server) getOrganizations(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	organizations := make([]pritunl.Organization, 0, len(s.organizations))
+	for _, id := range sortedKeys(s.organizations) {
+		organizations = append(organizations, *s.organizations[id])
+	}
+
+	writeList(s, w, r, "organizations", organizations, false)
+}
+
+func (s *Server) getOrganization(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	organization, ok := s.organizations[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "organization not found")
+		return
+	}
+
+	writeJSON(w, organization)
+}
+
+func (s *Server) createOrganization(w http.ResponseWriter, r *http.Request) {
+	var organization pritunl.Organization
+	if err := json.NewDecoder(r.Body).Decode(&organization); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	organization.ID = s.nextID()
+	s.organizations[organization.ID] = &organization
+
+	writeJSON(w, organization)
+}
+
+func (s *Server) updateOrganization(w http.ResponseWriter, r *http.Request) {
+	var organization pritunl.Organization
+	if err := json.NewDecoder(r.Body).Decode(&organization); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.organizations[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "organization not found")
+		return
+	}
+	existing.Name = organization.Name
+
+	writeJSON(w, existing)
+}
+
+func (s *Server) deleteOrganization(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	delete(s.organizations, id)
+	for _, state := range s.servers {
+		state.organizationIds = removeValue(state.organizationIds, id)
+	}
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (s *Server) getUsers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := make([]pritunl.User, 0)
+	for _, id := range sortedKeys(s.users) {
+		if s.users[id].Organization == r.PathValue("organization") {
+			users = append(users, *s.users[id])
+		}
+	}
+
+	writeList(s, w, r, "users", users, false)
+}
+
+// getUserAudit returns the events of the user, the newest first the way Pritunl does
+func (s *Server) getUserAudit(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[r.PathValue("id")]
+	if !ok || user.Organization != r.PathValue("organization") {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	events := make([]pritunl.AuditEvent, len(s.userAudit[user.ID]))
+	copy(events, s.userAudit[user.ID])
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp > events[j].Timestamp
+	})
+
+	writeJSON(w, events)
+}
+
+func (s *Server) getServers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	servers := make([]serverJSON, 0, len(s.servers))
+	for _, id := range sortedKeys(s.servers) {
+		servers = append(servers, serverJSON(s.servers[id].server))
+	}
+
+	writeList(s, w, r, "servers", servers, false)
+}
+
+func (s *Server) getServer(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+
+	writeJSON(w, serverJSON(state.server))
+}
+
+func (s *Server) createServer(w http.ResponseWriter, r *http.Request) {
+	server, err := decodeServer(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	server.ID = s.nextID()
+	server.Status = pritunl.ServerStatusOffline
+	s.servers[server.ID] = &serverState{server: server, links: make(map[string]bool)}
+
+	writeJSON(w, serverJSON(server))
+}
+
+func (s *Server) updateServer(w http.ResponseWriter, r *http.Request) {
+	server, err := decodeServer(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+	server.ID = state.server.ID
+	server.Status = state.server.Status
+	state.server = server
+
+	writeJSON(w, serverJSON(server))
+}
+
+func (s *Server) deleteServer(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	delete(s.servers, id)
+	for _, state := range s.servers {
+		delete(state.links, id)
+	}
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (s *Server) operateServer(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+
+	switch r.PathValue("operation") {
+	case "start", "restart":
+		state.server.Status = pritunl.ServerStatusOnline
+	case "stop":
+		state.server.Status = pritunl.ServerStatusOffline
+	default:
+		writeError(w, http.StatusBadRequest, "unknown operation")
+		return
+	}
+
+	writeJSON(w, serverJSON(state.server))
+}
+
+func (s *Server) getServerOutput(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+
+	output := make([]string, len(state.output))
+	copy(output, state.output)
+
+	writeJSON(w, map[string]interface{}{"id": state.server.ID, "output": output})
+}
+
+func (s *Server) getServerBandwidth(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+
+	period := r.PathValue("period")
+	for _, known := range pritunl.MetricPeriods {
+		if period == known {
+			bandwidth := state.bandwidth[period]
+			if bandwidth.Received == nil {
+				bandwidth.Received = []pritunl.BandwidthPoint{}
+			}
+			if bandwidth.Sent == nil {
+				bandwidth.Sent = []pritunl.BandwidthPoint{}
+			}
+
+			writeJSON(w, bandwidth)
+			return
+		}
+	}
+
+	writeError(w, http.StatusBadRequest, "unknown period")
+}
+
+func (s *Server) getServerOrganizations(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+
+	organizations := make([]pritunl.Organization, 0, len(state.organizationIds))
+	for _, id := range state.organizationIds {
+		if organization, ok := s.organizations[id]; ok {
+			organizations = append(organizations, *organization)
+		}
+	}
+
+	writeJSON(w, organizations)
+}
+
+func (s *Server) attachServerOrganization(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+	if _, ok := s.organizations[r.PathValue("organization")]; !ok {
+		writeError(w, http.StatusNotFound, "organization not found")
+		return
+	}
+	state.organizationIds = appendUnique(state.organizationIds, r.PathValue("organization"))
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (s *Server) detachServerOrganization(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+	state.organizationIds = removeValue(state.organizationIds, r.PathValue("organization"))
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (s *Server) getServerRoutes(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+
+	routes := make([]pritunl.Route, len(state.routes))
+	copy(routes, state.routes)
+
+	// the networks of the linked servers are routed the way Pritunl does
+	for _, id := range sortedKeys(state.links) {
+		if linked, ok := s.servers[id]; ok {
+			routes = append(routes, pritunl.Route{Network: linked.server.Network, ServerLink: true})
+		}
+	}
+
+	writeJSON(w, routes)
+}
+
+func (s *Server) addServerRoute(w http.ResponseWriter, r *http.Request) {
+	var route pritunl.Route
+	if err := json.NewDecoder(r.Body).Decode(&route); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+	route.Network = pritunl.NormalizeNetwork(route.Network)
+	state.routes = append(state.routes, route)
+
+	writeJSON(w, route)
+}
+
+func (s *Server) addServerRoutes(w http.ResponseWriter, r *http.Request) {
+	var routes []pritunl.Route
+	if err := json.NewDecoder(r.Body).Decode(&routes); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+	for _, route := range routes {
+		route.Network = pritunl.NormalizeNetwork(route.Network)
+		state.routes = append(state.routes, route)
+	}
+
+	writeJSON(w, routes)
+}
+
+func (s *Server) updateServerRoute(w http.ResponseWriter, r *http.Request) {
+	var route pritunl.Route
+	if err := json.NewDecoder(r.Body).Decode(&route); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+	for i, v := range state.routes {
+		if v.GetID() == r.PathValue("route") {
+			route.Network = v.Network
+			state.routes[i] = route
+			writeJSON(w, route)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "route not found")
+}
+
+func (s *Server) deleteServerRoute(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+	routes := make([]pritunl.Route, 0, len(state.routes))
+	for _, v := range state.routes {
+		if v.GetID() != r.PathValue("route") {
+			routes = append(routes, v)
+		}
+	}
+	state.routes = routes
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (s *Server) getServerHosts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+
+	hosts := make([]pritunl.Host, 0, len(state.hostIds))
+	for _, id := range state.hostIds {
+		if host, ok := s.hosts[id]; ok {
+			hosts = append(hosts, *host)
+		}
+	}
+
+	writeJSON(w, hosts)
+}
+
+func (s *Server) attachServerHost(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+	if _, ok := s.hosts[r.PathValue("host")]; !ok {
+		writeError(w, http.StatusNotFound, "host not found")
+		return
+	}
+	state.hostIds = appendUnique(state.hostIds, r.PathValue("host"))
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (s *Server) detachServerHost(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+	state.hostIds = removeValue(state.hostIds, r.PathValue("host"))
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (s *Server) getServerLinks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.servers[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "server not found")
+		return
+	}
+
+	links := make([]pritunl.ServerLink, 0, len(state.links))
+	for _, id := range sortedKeys(state.links) {
+		if linked, ok := s.servers[id]; ok {
+			links = append(links, pritunl.ServerLink{
+				ID:              id,
+				Name:            linked.server.Name,
+				Status:          linked.server.Status,
+				UseLocalAddress: state.links[id],
+			})
+		}
+	}
+
+	writeJSON(w, links)
+}
+
+// attachServerLink links both servers, which must be offline the way Pritunl requires
+func (s *Server) attachServerLink(w http.ResponseWriter, r *http.Request) {
+	var link pritunl.ServerLink
+	if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, linked, ok := s.linkedServers(w, r)
+	if !ok {
+		return
+	}
+
+	state.links[r.PathValue("link")] = link.UseLocalAddress
+	linked.links[r.PathValue("id")] = link.UseLocalAddress
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (s *Server) detachServerLink(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, linked, ok := s.linkedServers(w, r)
+	if !ok {
+		return
+	}
+
+	delete(state.links, r.PathValue("link"))
+	delete(linked.links, r.PathValue("id"))
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+// linkedServers returns the servers of the link request and writes the error when one is missing or online.
+// It must be called with the lock held.
+func (s *Server) linkedServers(w http.ResponseWriter, r *http.Request) (*serverState, *serverState, bool) {
+	state, ok := s.servers[r.PathValue("id")]
+	linked, linkedOk := s.servers[r.PathValue("link")]
+	if !ok || !linkedOk {
+		writeError(w, http.StatusNotFound, "server not found")
+		return nil, nil, false
+	}
+
+	if state.server.Status != pritunl.ServerStatusOffline || linked.server.Status != pritunl.ServerStatusOffline {
+		writeError(w, http.StatusBadRequest, "Server link can not be changed while servers are online.")
+		return nil, nil, false
+	}
+
+	return state, linked, true
+}
+
+func (s *Server) getHosts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hosts := make([]pritunl.Host, 0, len(s.hosts))
+	for _, id := range sortedKeys(s.hosts) {
+		hosts = append(hosts, *s.hosts[id])
+	}
+
+	writeList(s, w, r, "hosts", hosts, false)
+}
+
+func (s *Server) getHost(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	host, ok := s.hosts[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "host not found")
+		return
+	}
+
+	writeJSON(w, host)
+}
+
+func (s *Server) getHostUsage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.hosts[r.PathValue("id")]; !ok {
+		writeError(w, http.StatusNotFound, "host not found")
+		return
+	}
+
+	period := r.PathValue("period")
+	for _, known := range pritunl.MetricPeriods {
+		if period == known {
+			usage := s.hostUsage[r.PathValue("id")][period]
+			if usage.CPU == nil {
+				usage.CPU = []pritunl.UsagePoint{}
+			}
+			if usage.Memory == nil {
+				usage.Memory = []pritunl.UsagePoint{}
+			}
+
+			writeJSON(w, usage)
+			return
+		}
+	}
+
+	writeError(w, http.StatusBadRequest, "unknown period")
+}
+
+// updateHost stores the settable fields, the addresses in use follow the overrides the way Pritunl does
+func (s *Server) updateHost(w http.ResponseWriter, r *http.Request) {
+	var host pritunl.Host
+	if err := json.NewDecoder(r.Body).Decode(&host); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.hosts[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "host not found")
+		return
+	}
+
+	existing.Name = host.Name
+	existing.PublicAddress = host.PublicAddress
+	existing.PublicAddress6 = host.PublicAddress6
+	existing.LocalAddress = host.LocalAddress
+	existing.LocalAddress6 = host.LocalAddress6
+	existing.LinkAddress = host.LinkAddress
+	existing.SyncAddress = host.SyncAddress
+	existing.RoutedSubnet6 = host.RoutedSubnet6
+	existing.RoutedSubnet6WG = host.RoutedSubnet6WG
+	existing.AvailabilityGroup = host.AvailabilityGroup
+
+	for _, address := range []struct {
+		inUse    *string
+		override string
+	}{
+		{&existing.PublicAddr, host.PublicAddress},
+		{&existing.PublicAddr6, host.PublicAddress6},
+		{&existing.LocalAddr, host.LocalAddress},
+		{&existing.LocalAddr6, host.LocalAddress6},
+		{&existing.LinkAddr, host.LinkAddress},
+	} {
+		if address.override != "" {
+			*address.inUse = address.override
+		}
+	}
+
+	writeJSON(w, existing)
+}
+
+func (s *Server) deleteHost(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	delete(s.hosts, id)
+	for _, state := range s.servers {
+		state.hostIds = removeValue(state.hostIds, id)
+	}
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (s *Server) getLinks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links := make([]pritunl.Link, 0, len(s.links))
+	for _, id := range sortedKeys(s.links) {
+		links = append(links, *s.links[id])
+	}
+
+	// links are always paginated
+	writeList(s, w, r, "links", links, true)
+}
+
+// serverJSON is encoded the way Pritunl returns servers, pritunl.Server encodes mss_fix as a string for requests
+type serverJSON pritunl.Server
+
+// decodeServer decodes the server sent by the client, which sends mss_fix as a string
+func decodeServer(r *http.Request) (pritunl.Server, error) {
+	var input struct {
+		serverJSON
+		MssFix json.Number `json:"mss_fix"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return pritunl.Server{}, err
+	}
+
+	server := pritunl.Server(input.serverJSON)
+	if input.MssFix != "" {
+		mssFix, err := input.MssFix.Int64()
+		if err != nil {
+			return pritunl.Server{}, err
+		}
+		server.MssFix = int(mssFix)
+	}
+
+	return server, nil
+}
+
+func (s *Server) getAdministrators(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	administrators := make([]pritunl.Administrator, 0, len(s.admins))
+	for _, id := range sortedKeys(s.admins) {
+		administrators = append(administrators, *s.admins[id])
+	}
+
+	writeJSON(w, administrators)
+}
+
+func (s *Server) getAdministrator(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	administrator, ok := s.admins[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "administrator not found")
+		return
+	}
+
+	writeJSON(w, administrator)
+}
+
+// createAdministrator generates the API key of the administrator, the password is never returned
+func (s *Server) createAdministrator(w http.ResponseWriter, r *http.Request) {
+	var administrator pritunl.Administrator
+	if err := json.NewDecoder(r.Body).Decode(&administrator); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, s.AddAdministrator(pritunl.Administrator{
+		Username:  administrator.Username,
+		YubikeyID: administrator.YubikeyID,
+		OtpAuth:   administrator.OtpAuth,
+		AuthAPI:   administrator.AuthAPI,
+		Disabled:  administrator.Disabled,
+		SuperUser: administrator.SuperUser,
+	}))
+}
+
+// updateAdministrator changes the fields in the request, "token" and "secret" set to true regenerate the API key the way Pritunl does
+func (s *Server) updateAdministrator(w http.ResponseWriter, r *http.Request) {
+	var update map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.admins[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "administrator not found")
+		return
+	}
+
+	for key, value := range map[string]interface{}{
+		"username":   &existing.Username,
+		"yubikey_id": &existing.YubikeyID,
+		"otp_auth":   &existing.OtpAuth,
+		"auth_api":   &existing.AuthAPI,
+		"disabled":   &existing.Disabled,
+		"super_user": &existing.SuperUser,
+	} {
+		if raw, ok := update[key]; ok {
+			if err := json.Unmarshal(raw, value); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+	}
+	if string(update["token"]) == "true" {
+		existing.Token = s.nextID()
+	}
+	if string(update["secret"]) == "true" {
+		existing.Secret = s.nextID()
+	}
+
+	writeJSON(w, existing)
+}
+
+func (s *Server) deleteAdministrator(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.admins, r.PathValue("id"))
+
+	writeJSON(w, map[string]interface{}{})
+}
+
+func (s *Server) getSettings(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, s.settings)
+}
+
+// updateSettings changes only the settings in the request
+func (s *Server) updateSettings(w http.ResponseWriter, r *http.Request) {
+	var settings map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.SetSettings(settings)
+
+	writeJSON(w, s.Settings())
+}
@@ -0,0 +1,471 @@
+// Package pritunltest provides an in-memory fake of the Pritunl API for tests and benchmarks.
+package pritunltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+// Server is a fake Pritunl API server keeping its objects in memory
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	latency       time.Duration
+	pageSize      int
+	lastID        int
+	requests      map[string]int
+	failures      map[string]int
+	organizations map[string]*pritunl.Organization
+	servers       map[string]*serverState
+	hosts         map[string]*pritunl.Host
+	links         map[string]*pritunl.Link
+	admins        map[string]*pritunl.Administrator
+	users         map[string]*pritunl.User
+	hostUsage     map[string]map[string]pritunl.HostUsage
+	userAudit     map[string][]pritunl.AuditEvent
+	sessions      map[string]string
+	settings      map[string]interface{}
+}
+
+type serverState struct {
+	server          pritunl.Server
+	organizationIds []string
+	routes          []pritunl.Route
+	hostIds         []string
+	links           map[string]bool
+	output          []string
+	bandwidth       map[string]pritunl.ServerBandwidth
+}
+
+// NewServer starts the fake server, it must be closed by the caller
+func NewServer() *Server {
+	s := &Server{
+		requests:      make(map[string]int),
+		failures:      make(map[string]int),
+		organizations: make(map[string]*pritunl.Organization),
+		servers:       make(map[string]*serverState),
+		hosts:         make(map[string]*pritunl.Host),
+		links:         make(map[string]*pritunl.Link),
+		admins:        make(map[string]*pritunl.Administrator),
+		users:         make(map[string]*pritunl.User),
+		hostUsage:     make(map[string]map[string]pritunl.HostUsage),
+		userAudit:     make(map[string][]pritunl.AuditEvent),
+		sessions:      make(map[string]string),
+		settings:      make(map[string]interface{}),
+	}
+
+	mux := http.NewServeMux()
+
+	s.handle(mux, "POST /auth/session", s.createSession)
+	s.handle(mux, "GET /state", s.getState)
+
+	s.handle(mux, "GET /organization", s.getOrganizations)
+	s.handle(mux, "GET /organization/{id}", s.getOrganization)
+	s.handle(mux, "POST /organization", s.createOrganization)
+	s.handle(mux, "PUT /organization/{id}", s.updateOrganization)
+	s.handle(mux, "DELETE /organization/{id}", s.deleteOrganization)
+
+	s.handle(mux, "GET /user/{organization}", s.getUsers)
+	s.handle(mux, "GET /user/{organization}/{id}/audit", s.getUserAudit)
+
+	s.handle(mux, "GET /server", s.getServers)
+	s.handle(mux, "GET /server/{id}", s.getServer)
+	s.handle(mux, "POST /server", s.createServer)
+	s.handle(mux, "PUT /server/{id}", s.updateServer)
+	s.handle(mux, "DELETE /server/{id}", s.deleteServer)
+	s.handle(mux, "PUT /server/{id}/operation/{operation}", s.operateServer)
+	s.handle(mux, "GET /server/{id}/output", s.getServerOutput)
+	s.handle(mux, "GET /server/{id}/bandwidth/{period}", s.getServerBandwidth)
+
+	s.handle(mux, "GET /server/{id}/organization", s.getServerOrganizations)
+	s.handle(mux, "PUT /server/{id}/organization/{organization}", s.attachServerOrganization)
+	s.handle(mux, "DELETE /server/{id}/organization/{organization}", s.detachServerOrganization)
+
+	s.handle(mux, "GET /server/{id}/route", s.getServerRoutes)
+	s.handle(mux, "POST /server/{id}/route", s.addServerRoute)
+	s.handle(mux, "POST /server/{id}/routes", s.addServerRoutes)
+	s.handle(mux, "PUT /server/{id}/route/{route}", s.updateServerRoute)
+	s.handle(mux, "DELETE /server/{id}/route/{route}", s.deleteServerRoute)
+
+	s.handle(mux, "GET /server/{id}/host", s.getServerHosts)
+	s.handle(mux, "PUT /server/{id}/host/{host}", s.attachServerHost)
+	s.handle(mux, "DELETE /server/{id}/host/{host}", s.detachServerHost)
+
+	s.handle(mux, "GET /server/{id}/link", s.getServerLinks)
+	s.handle(mux, "PUT /server/{id}/link/{link}", s.attachServerLink)
+	s.handle(mux, "DELETE /server/{id}/link/{link}", s.detachServerLink)
+
+	s.handle(mux, "GET /host", s.getHosts)
+	s.handle(mux, "GET /host/{id}", s.getHost)
+	s.handle(mux, "GET /host/{id}/usage/{period}", s.getHostUsage)
+	s.handle(mux, "PUT /host/{id}", s.updateHost)
+	s.handle(mux, "DELETE /host/{id}", s.deleteHost)
+
+	s.handle(mux, "GET /link", s.getLinks)
+
+	s.handle(mux, "GET /admin", s.getAdministrators)
+	s.handle(mux, "GET /admin/{id}", s.getAdministrator)
+	s.handle(mux, "POST /admin", s.createAdministrator)
+	s.handle(mux, "PUT /admin/{id}", s.updateAdministrator)
+	s.handle(mux, "DELETE /admin/{id}", s.deleteAdministrator)
+
+	s.handle(mux, "GET /settings", s.getSettings)
+	s.handle(mux, "PUT /settings", s.updateSettings)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// NewTestServer starts the fake server and closes it when the test and its subtests complete
+func NewTestServer(tb testing.TB) *Server {
+	s := NewServer()
+	tb.Cleanup(s.Close)
+
+	return s
+}
+
+// NewClient returns a client of the Pritunl API pointed to the fake server
+func (s *Server) NewClient() pritunl.Client {
+	return pritunl.NewClient(s.URL, Token, Secret, false)
+}
+
+// SetLatency delays every response of the server
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = latency
+}
+
+// SetPageSize splits the lists requested with the page parameter into pages of the size, 0 returns a single page
+func (s *Server) SetPageSize(pageSize int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pageSize = pageSize
+}
+
+// FailRequests makes the requests matching the pattern, e.g. "GET /server/{id}/route", fail with the status code.
+// Status code 0 removes the failure.
+func (s *Server) FailRequests(pattern string, statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if statusCode == 0 {
+		delete(s.failures, pattern)
+		return
+	}
+	s.failures[pattern] = statusCode
+}
+
+// Requests returns the number of the requests received for the pattern
+func (s *Server) Requests(pattern string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[pattern]
+}
+
+// AddOrganization creates an organization
+func (s *Server) AddOrganization(name string) pritunl.Organization {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	organization := &pritunl.Organization{ID: s.nextID(), Name: name}
+	s.organizations[organization.ID] = organization
+
+	return *organization
+}
+
+// AddServer creates a server, the ID is generated when it's empty
+func (s *Server) AddServer(server pritunl.Server) pritunl.Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if server.ID == "" {
+		server.ID = s.nextID()
+	}
+	if server.Status == "" {
+		server.Status = pritunl.ServerStatusOffline
+	}
+	s.servers[server.ID] = &serverState{server: server, links: make(map[string]bool)}
+
+	return server
+}
+
+// AddServerOutput appends the lines to the output of the server
+func (s *Server) AddServerOutput(serverId string, lines ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.servers[serverId]; ok {
+		state.output = append(state.output, lines...)
+	}
+}
+
+// SetServerBandwidth sets the bandwidth of the server over the period
+func (s *Server) SetServerBandwidth(serverId string, period string, bandwidth pritunl.ServerBandwidth) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.servers[serverId]; ok {
+		if state.bandwidth == nil {
+			state.bandwidth = make(map[string]pritunl.ServerBandwidth)
+		}
+		state.bandwidth[period] = bandwidth
+	}
+}
+
+// AddUser creates a user in its organization, the ID is generated when it's empty
+func (s *Server) AddUser(user pritunl.User) pritunl.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user.ID == "" {
+		user.ID = s.nextID()
+	}
+	s.users[user.ID] = &user
+
+	return user
+}
+
+// AddUserAuditEvents records the events of the user, the ID of the events is generated when it's empty
+func (s *Server) AddUserAuditEvents(userId string, events ...pritunl.AuditEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, event := range events {
+		if event.ID == "" {
+			event.ID = s.nextID()
+		}
+		s.userAudit[userId] = append(s.userAudit[userId], event)
+	}
+}
+
+// AttachOrganization attaches the organization to the server
+func (s *Server) AttachOrganization(serverId, organizationId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.servers[serverId]; ok {
+		state.organizationIds = appendUnique(state.organizationIds, organizationId)
+	}
+}
+
+// AddRoute adds the route to the server
+func (s *Server) AddRoute(serverId string, route pritunl.Route) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.servers[serverId]; ok {
+		state.routes = append(state.routes, route)
+	}
+}
+
+// AddHost creates a host, the ID is generated when it's empty
+func (s *Server) AddHost(host pritunl.Host) pritunl.Host {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if host.ID == "" {
+		host.ID = s.nextID()
+	}
+	s.hosts[host.ID] = &host
+
+	return host
+}
+
+// SetHostUsage sets the usage of the host over the period
+func (s *Server) SetHostUsage(hostId string, period string, usage pritunl.HostUsage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.hostUsage[hostId] == nil {
+		s.hostUsage[hostId] = make(map[string]pritunl.HostUsage)
+	}
+	s.hostUsage[hostId][period] = usage
+}
+
+// AttachHost attaches the host to the server
+func (s *Server) AttachHost(serverId, hostId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.servers[serverId]; ok {
+		state.hostIds = appendUnique(state.hostIds, hostId)
+	}
+}
+
+// AddLink creates a link, the ID is generated when it's empty
+func (s *Server) AddLink(link pritunl.Link) pritunl.Link {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if link.ID == "" {
+		link.ID = s.nextID()
+	}
+	s.links[link.ID] = &link
+
+	return link
+}
+
+// AddAdministrator creates an administrator, the ID and the API key are generated when they are empty
+func (s *Server) AddAdministrator(administrator pritunl.Administrator) pritunl.Administrator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if administrator.ID == "" {
+		administrator.ID = s.nextID()
+	}
+	if administrator.Token == "" {
+		administrator.Token = s.nextID()
+	}
+	if administrator.Secret == "" {
+		administrator.Secret = s.nextID()
+	}
+	administrator.Password = ""
+	s.admins[administrator.ID] = &administrator
+
+	return administrator
+}
+
+// Settings returns a copy of the settings
+func (s *Server) Settings() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	settings := make(map[string]interface{}, len(s.settings))
+	for key, value := range s.settings {
+		settings[key] = value
+	}
+
+	return settings
+}
+
+// SetSettings changes the settings the way PUT /settings does
+func (s *Server) SetSettings(settings map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, value := range settings {
+		s.settings[key] = value
+	}
+}
+
+// handle registers the handler, authenticates and counts the requests and applies the latency and failures
+func (s *Server) handle(mux *http.ServeMux, pattern string, handler http.HandlerFunc) {
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if pattern != "POST /auth/session" && !s.authenticate(pattern, r) {
+			writeError(w, http.StatusUnauthorized, "Authentication credentials are not valid.")
+			return
+		}
+
+		s.mu.Lock()
+		s.requests[pattern]++
+		latency := s.latency
+		statusCode := s.failures[pattern]
+		s.mu.Unlock()
+
+		if latency > 0 {
+			time.Sleep(latency)
+		}
+
+		if statusCode != 0 {
+			writeError(w, statusCode, fmt.Sprintf("%s failed", pattern))
+			return
+		}
+
+		handler(w, r)
+	})
+}
+
+// nextID generates an ID looking like a Pritunl one, it must be called with the lock held
+func (s *Server) nextID() string {
+	s.lastID++
+	return fmt.Sprintf("%024x", s.lastID)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": "error", "error_msg": message})
+}
+
+// writeList writes the items as an array unless the page is requested, the way Pritunl does.
+// Paginated lists are objects with the items stored under the key, page_total is the index of the last page.
+// It must be called with the lock held.
+func writeList[T any](s *Server, w http.ResponseWriter, r *http.Request, key string, items []T, alwaysPaginated bool) {
+	if !r.URL.Query().Has("page") && !alwaysPaginated {
+		writeJSON(w, items)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageTotal := 0
+	if s.pageSize > 0 && len(items) > 0 {
+		pageTotal = (len(items) - 1) / s.pageSize
+
+		start := page * s.pageSize
+		end := start + s.pageSize
+		if start > len(items) {
+			start = len(items)
+		}
+		if end > len(items) {
+			end = len(items)
+		}
+		items = items[start:end]
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"page":       page,
+		"page_total": pageTotal,
+		key:          items,
+	})
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+
+	return append(list, value)
+}
+
+func removeValue(list []string, value string) []string {
+	result := make([]string, 0, len(list))
+	for _, v := range list {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
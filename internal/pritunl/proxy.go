@@ -0,0 +1,64 @@
+package pritunl
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyOptions configures the proxy the client reaches the Pritunl API through
+type ProxyOptions struct {
+	// URL of the proxy with the http, https or socks5 scheme, the proxy of the environment is used when it's empty
+	URL string
+	// Username and Password authenticate to the proxy, they override the credentials of the URL
+	Username string
+	Password string
+	// NoProxy is a comma-separated list of hosts, domains and networks reached directly, in the NO_PROXY format
+	NoProxy string
+}
+
+// Func builds the proxy function of the HTTP transport
+func (o ProxyOptions) Func() (func(*http.Request) (*url.URL, error), error) {
+	if o.URL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyUrl, err := url.Parse(o.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %s", err)
+	}
+
+	switch proxyUrl.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy url scheme %q, must be http, https or socks5", proxyUrl.Scheme)
+	}
+	if proxyUrl.Host == "" {
+		return nil, fmt.Errorf("invalid proxy url %q: missing host", o.URL)
+	}
+
+	if o.Username != "" || o.Password != "" {
+		proxyUrl.User = url.UserPassword(o.Username, o.Password)
+	}
+
+	// the configuration is scoped to the client, the proxy environment variables are ignored
+	config := &httpproxy.Config{
+		HTTPProxy:  proxyUrl.String(),
+		HTTPSProxy: proxyUrl.String(),
+		NoProxy:    o.NoProxy,
+	}
+	proxyFunc := config.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
+
+// WithProxy replaces the proxy of the environment used by the client
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) ClientOption {
+	return func(o *clientOptions) {
+		o.proxy = proxy
+	}
+}
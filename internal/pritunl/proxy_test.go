@@ -0,0 +1,329 @@
+package pritunl
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// the API host doesn't resolve, so it can only be reached through the proxies of the tests
+const proxyTestBaseUrl = "http://pritunl.internal"
+
+func TestProxyOptionsHTTP(t *testing.T) {
+	backend := newProxyTestBackend()
+	defer backend.Close()
+
+	backendUrl, _ := url.Parse(backend.URL)
+	reverseProxy := httputil.NewSingleHostReverseProxy(backendUrl)
+
+	var mu sync.Mutex
+	var hosts []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expected := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:pass"))
+		if r.Header.Get("Proxy-Authorization") != expected {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+
+		mu.Lock()
+		hosts = append(hosts, r.Host)
+		mu.Unlock()
+
+		reverseProxy.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	t.Run("with the proxy credentials", func(t *testing.T) {
+		apiClient := newProxyTestClient(t, ProxyOptions{URL: proxy.URL, Username: "user", Password: "pass"})
+		if err := apiClient.TestApiCall(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(hosts) != 1 || hosts[0] != "pritunl.internal" {
+			t.Fatalf("expected a request to pritunl.internal through the proxy, got %v", hosts)
+		}
+	})
+
+	t.Run("with the credentials in the url", func(t *testing.T) {
+		proxyUrl, _ := url.Parse(proxy.URL)
+		proxyUrl.User = url.UserPassword("user", "pass")
+
+		apiClient := newProxyTestClient(t, ProxyOptions{URL: proxyUrl.String()})
+		if err := apiClient.TestApiCall(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("without the proxy credentials", func(t *testing.T) {
+		apiClient := newProxyTestClient(t, ProxyOptions{URL: proxy.URL})
+		err := apiClient.TestApiCall()
+		if err == nil || !strings.Contains(err.Error(), "code=407") {
+			t.Fatalf("expected a 407 response, got %v", err)
+		}
+	})
+}
+
+func TestProxyOptionsSOCKS5(t *testing.T) {
+	backend := newProxyTestBackend()
+	defer backend.Close()
+
+	proxy := newSOCKS5Proxy(t, backend.Listener.Addr().String(), "user", "pass")
+	defer proxy.Close()
+
+	t.Run("with the proxy credentials", func(t *testing.T) {
+		apiClient := newProxyTestClient(t, ProxyOptions{URL: "socks5://" + proxy.Addr().String(), Username: "user", Password: "pass"})
+		if err := apiClient.TestApiCall(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if addresses := proxy.addresses(); len(addresses) != 1 || addresses[0] != "pritunl.internal:80" {
+			t.Fatalf("expected a connection to pritunl.internal:80 through the proxy, got %v", addresses)
+		}
+	})
+
+	t.Run("without the proxy credentials", func(t *testing.T) {
+		apiClient := newProxyTestClient(t, ProxyOptions{URL: "socks5://" + proxy.Addr().String()})
+		if err := apiClient.TestApiCall(); err == nil {
+			t.Fatalf("expected an error")
+		}
+	})
+}
+
+func TestProxyOptionsFunc(t *testing.T) {
+	proxyFunc, err := ProxyOptions{URL: "http://proxy.internal:3128", NoProxy: "pritunl.internal,.example.com,10.0.0.0/8"}.Func()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		url      string
+		expected string
+	}{
+		{url: "https://pritunl.example.org/state", expected: "http://proxy.internal:3128"},
+		{url: "https://pritunl.internal/state"},
+		{url: "https://pritunl.example.com/state"},
+		{url: "https://10.1.2.3/state"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tc.url, nil)
+
+			proxyUrl, err := proxyFunc(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			actual := ""
+			if proxyUrl != nil {
+				actual = proxyUrl.String()
+			}
+			if actual != tc.expected {
+				t.Fatalf("expected proxy %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"ftp://proxy.internal", "proxy.internal:3128", "http://"} {
+		if _, err := (ProxyOptions{URL: invalid}).Func(); err == nil {
+			t.Fatalf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func newProxyTestBackend() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+}
+
+func newProxyTestClient(t *testing.T, options ProxyOptions) Client {
+	t.Helper()
+
+	proxyFunc, err := options.Func()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return NewClient(proxyTestBaseUrl, "token", "secret", false, WithProxy(proxyFunc), WithMaxRetries(0))
+}
+
+// socks5Proxy is a minimal SOCKS5 server with the username/password authentication,
+// it connects every client to the target regardless of the requested address
+type socks5Proxy struct {
+	net.Listener
+
+	target   string
+	username string
+	password string
+
+	mu        sync.Mutex
+	requested []string
+}
+
+func newSOCKS5Proxy(t *testing.T, target, username, password string) *socks5Proxy {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proxy := &socks5Proxy{Listener: listener, target: target, username: username, password: password}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go proxy.serve(conn)
+		}
+	}()
+
+	return proxy
+}
+
+func (p *socks5Proxy) addresses() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]string(nil), p.requested...)
+}
+
+func (p *socks5Proxy) serve(conn net.Conn) {
+	defer conn.Close()
+
+	address, err := p.handshake(conn)
+	if err != nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.requested = append(p.requested, address)
+	p.mu.Unlock()
+
+	target, err := net.Dial("tcp", p.target)
+	if err != nil {
+		conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer target.Close()
+
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		return
+	}
+
+	go io.Copy(target, conn)
+	io.Copy(conn, target)
+}
+
+// handshake authenticates the client and returns the address of the connect request
+func (p *socks5Proxy) handshake(conn net.Conn) (string, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", err
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return "", err
+	}
+
+	// only the username/password authentication is accepted
+	if !strings.Contains(string(methods), "\x02") {
+		conn.Write([]byte{5, 0xff})
+		return "", errors.New("no acceptable authentication method")
+	}
+	if _, err := conn.Write([]byte{5, 2}); err != nil {
+		return "", err
+	}
+
+	username, password, err := readSOCKS5Credentials(conn)
+	if err != nil {
+		return "", err
+	}
+	if username != p.username || password != p.password {
+		conn.Write([]byte{1, 1})
+		return "", errors.New("invalid credentials")
+	}
+	if _, err := conn.Write([]byte{1, 0}); err != nil {
+		return "", err
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return "", err
+	}
+
+	var host string
+	switch request[3] {
+	case 1, 4:
+		ip := make([]byte, 4)
+		if request[3] == 4 {
+			ip = make([]byte, 16)
+		}
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", err
+		}
+		host = net.IP(ip).String()
+	case 3:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return "", err
+		}
+		name := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return "", err
+		}
+		host = string(name)
+	default:
+		return "", errors.New("unsupported address type")
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+func readSOCKS5Credentials(conn net.Conn) (string, string, error) {
+	readField := func() (string, error) {
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return "", err
+		}
+		field := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, field); err != nil {
+			return "", err
+		}
+		return string(field), nil
+	}
+
+	version := make([]byte, 1)
+	if _, err := io.ReadFull(conn, version); err != nil {
+		return "", "", err
+	}
+	username, err := readField()
+	if err != nil {
+		return "", "", err
+	}
+	password, err := readField()
+	if err != nil {
+		return "", "", err
+	}
+
+	return username, password, nil
+}
@@ -0,0 +1,88 @@
+package pritunl
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+
+	// retryBaseDelay is used when the response has no Retry-After header, it doubles on every attempt
+	retryBaseDelay = time.Second
+	// retryMaxDelay caps the delay requested by the server
+	retryMaxDelay = time.Minute
+)
+
+// retryTransport retries the requests rejected by the API because it's overloaded
+type retryTransport struct {
+	underlyingTransport http.RoundTripper
+	maxRetries          int
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("can't retry %s %s: the request body can't be rewound", req.Method, req.URL.Path)
+			}
+
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.underlyingTransport.RoundTrip(attemptReq)
+		if err != nil || !isRetryableStatus(resp.StatusCode) || attempt >= t.maxRetries {
+			return resp, err
+		}
+
+		delay := retryDelay(resp.Header.Get("Retry-After"), attempt, time.Now())
+		log.Printf("[DEBUG] pritunl: %s %s responded with %d, retrying in %s (attempt %d of %d)", req.Method, req.URL.Path, resp.StatusCode, delay, attempt+1, t.maxRetries)
+
+		// the connection can be reused only when the body is read to the end
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable
+}
+
+// retryDelay parses the Retry-After header, which is either a number of seconds or an HTTP date,
+// and falls back to the exponential backoff when the header is missing or invalid
+func retryDelay(retryAfter string, attempt int, now time.Time) time.Duration {
+	delay := retryBaseDelay << attempt
+
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(retryAfter); err == nil {
+		delay = date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+	}
+
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+
+	return delay
+}
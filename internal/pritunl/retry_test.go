@@ -0,0 +1,148 @@
+package pritunl
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		retryAfter string
+		attempt    int
+		expected   time.Duration
+	}{
+		{name: "seconds", retryAfter: "5", expected: 5 * time.Second},
+		{name: "zero seconds", retryAfter: "0", expected: 0},
+		{name: "HTTP date", retryAfter: "Mon, 01 Jan 2024 00:00:10 GMT", expected: 10 * time.Second},
+		{name: "HTTP date in the past", retryAfter: "Sun, 31 Dec 2023 23:59:00 GMT", expected: 0},
+		{name: "capped delay", retryAfter: "3600", expected: retryMaxDelay},
+		{name: "backoff without the header", attempt: 2, expected: 4 * retryBaseDelay},
+		{name: "backoff with an invalid header", retryAfter: "soon", expected: retryBaseDelay},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			delay := retryDelay(testCase.retryAfter, testCase.attempt, now)
+			if delay != testCase.expected {
+				t.Fatalf("expected %s, got %s", testCase.expected, delay)
+			}
+		})
+	}
+}
+
+func TestClientRetriesOverloadedRequests(t *testing.T) {
+	var requests atomic.Int32
+	var signatures sync.Map
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.Header.Values("Auth-Signature")) != 1 {
+			t.Errorf("expected a single signature, got %v", r.Header.Values("Auth-Signature"))
+		}
+		signatures.Store(r.Header.Get("Auth-Nonce"), true)
+
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name": "org"}` {
+			t.Errorf("unexpected body %q", body)
+		}
+
+		switch requests.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"id": "org-id", "name": "org"}`))
+		}
+	}))
+	defer server.Close()
+
+	apiClient := NewClient(server.URL, "token", "secret", false)
+
+	organization, err := apiClient.CreateOrganization("org")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if organization.ID != "org-id" {
+		t.Fatalf("unexpected organization %+v", organization)
+	}
+	if requests.Load() != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests.Load())
+	}
+
+	nonces := 0
+	signatures.Range(func(_, _ any) bool {
+		nonces++
+		return true
+	})
+	if nonces != 3 {
+		t.Fatalf("expected every attempt to be signed separately, got %d nonces", nonces)
+	}
+}
+
+func TestClientGivesUpRetrying(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	apiClient := NewClient(server.URL, "token", "secret", false, WithMaxRetries(2))
+
+	if _, err := apiClient.GetServers(); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if requests.Load() != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests.Load())
+	}
+}
+
+func TestClientLimitsConcurrentRequests(t *testing.T) {
+	var running, maxRunning atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			max := maxRunning.Load()
+			if current <= max || maxRunning.CompareAndSwap(max, current) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	apiClient := NewClient(server.URL, "token", "secret", false, WithMaxConcurrentRequests(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := apiClient.GetServers(); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxRunning.Load() != 2 {
+		t.Fatalf("expected at most 2 concurrent requests, got %d", maxRunning.Load())
+	}
+}
@@ -21,9 +21,10 @@ type Route struct {
 	NatNetmap      string `json:"nat_netmap,omitempty"`
 }
 
+// GetID returns the ID Pritunl uses for the route, which is the hex encoded canonical network
 func (r Route) GetID() string {
 	if len(r.Network) > 0 {
-		return hex.EncodeToString([]byte(r.Network))
+		return hex.EncodeToString([]byte(NormalizeNetwork(r.Network)))
 	}
 
 	return ""
@@ -33,7 +34,7 @@ func ConvertMapToRoute(data map[string]interface{}) Route {
 	var route Route
 
 	if v, ok := data["network"]; ok {
-		route.Network = v.(string)
+		route.Network = NormalizeNetwork(v.(string))
 	}
 	if v, ok := data["comment"]; ok {
 		route.Comment = v.(string)
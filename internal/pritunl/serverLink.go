@@ -0,0 +1,10 @@
+package pritunl
+
+// ServerLink is a server linked to another one, the clients of both servers can reach each other.
+// UseLocalAddress connects the servers with the local addresses of their hosts instead of the public ones.
+type ServerLink struct {
+	ID              string `json:"id,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Status          string `json:"status,omitempty"`
+	UseLocalAddress bool   `json:"use_local_address"`
+}
@@ -0,0 +1,68 @@
+package pritunl
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MetricPeriods are the periods of the bandwidth and usage series, each one has its own sampling interval
+var MetricPeriods = []string{"1m", "5m", "30m", "2h", "1d"}
+
+// BandwidthPoint is a sample of a bandwidth series, the bytes transferred since the previous sample
+type BandwidthPoint struct {
+	Timestamp int64
+	Bytes     int64
+}
+
+// UnmarshalJSON decodes the [timestamp, bytes] pairs Pritunl returns
+func (p *BandwidthPoint) UnmarshalJSON(data []byte) error {
+	var pair []float64
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("expected a [timestamp, bytes] pair, got %s", data)
+	}
+
+	p.Timestamp = int64(pair[0])
+	p.Bytes = int64(pair[1])
+
+	return nil
+}
+
+// MarshalJSON encodes the point as a [timestamp, bytes] pair
+func (p BandwidthPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]int64{p.Timestamp, p.Bytes})
+}
+
+// ServerBandwidth is the bandwidth of a server over a period, the totals are the sums of the series
+type ServerBandwidth struct {
+	Received      []BandwidthPoint `json:"received"`
+	ReceivedTotal int64            `json:"received_total"`
+	Sent          []BandwidthPoint `json:"sent"`
+	SentTotal     int64            `json:"sent_total"`
+}
+
+// UserServer is a server the user is connected to, Pritunl reports them in the servers field of the users
+type UserServer struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Status          bool   `json:"status"`
+	ServerID        string `json:"server_id"`
+	DeviceName      string `json:"device_name"`
+	Platform        string `json:"platform"`
+	RealAddress     string `json:"real_address"`
+	VirtualAddress  string `json:"virtual_address"`
+	VirtualAddress6 string `json:"virtual_address6"`
+	ConnectedSince  int64  `json:"connected_since"`
+}
+
+// ServerClient is a client connected to a server with its user and organization
+type ServerClient struct {
+	UserServer
+
+	UserID           string
+	UserName         string
+	OrganizationID   string
+	OrganizationName string
+}
@@ -0,0 +1,196 @@
+package pritunl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/cookiejar"
+	"sync"
+)
+
+// sessionTransport authenticates the requests with the session of an administrator instead of the API token,
+// for the Pritunl servers with the API keys disabled
+type sessionTransport struct {
+	underlyingTransport http.RoundTripper
+	baseUrl             string
+	username            string
+	password            string
+
+	mu   sync.Mutex
+	jar  http.CookieJar
+	csrf string
+	// generation counts the logins, a rejected request logs in again only when no other request did it meanwhile
+	generation uint64
+}
+
+func newSessionTransport(underlyingTransport http.RoundTripper, baseUrl, username, password string) *sessionTransport {
+	jar, _ := cookiejar.New(nil)
+
+	return &sessionTransport{
+		underlyingTransport: underlyingTransport,
+		baseUrl:             baseUrl,
+		username:            username,
+		password:            password,
+		jar:                 jar,
+	}
+}
+
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the request must not be modified by the transport, it can be sent again on retries
+	req = req.Clone(req.Context())
+
+	if err := resolveURL(t.baseUrl, req); err != nil {
+		return nil, err
+	}
+
+	generation, err := t.session(0)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// the session expired, the request is sent once more after logging in again
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	readAndClose(resp)
+	log.Printf("[DEBUG] pritunl: %s %s was rejected, logging in again", req.Method, req.URL.Path)
+
+	if _, err := t.session(generation); err != nil {
+		return nil, err
+	}
+	if req.Body != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+	}
+
+	resp, err = t.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	return nil, fmt.Errorf("unauthorized: %s %s was rejected after logging in again\nbody=%s", req.Method, req.URL.Path, readAndClose(resp))
+}
+
+// session logs in when there is no session yet or the session of the generation was rejected,
+// and returns the generation of the current session
+func (t *sessionTransport) session(rejected uint64) (uint64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.generation != rejected {
+		return t.generation, nil
+	}
+
+	if err := t.login(); err != nil {
+		return 0, err
+	}
+	t.generation++
+
+	return t.generation, nil
+}
+
+// login creates the session and fetches its CSRF token, it must be called with the lock held
+func (t *sessionTransport) login() error {
+	credentials, _ := json.Marshal(map[string]string{
+		"username": t.username,
+		"password": t.password,
+	})
+
+	req, err := http.NewRequest("POST", "/auth/session", bytes.NewBuffer(credentials))
+	if err != nil {
+		return err
+	}
+	if err := resolveURL(t.baseUrl, req); err != nil {
+		return err
+	}
+
+	resp, err := t.sendWithCookies(req, "")
+	if err != nil {
+		return fmt.Errorf("failed to log in: %s", err)
+	}
+	body := readAndClose(resp)
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("unauthorized: the username or the password is invalid\nbody=%s", body)
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Non-200 response on logging in\ncode=%d\nbody=%s", resp.StatusCode, body)
+	}
+
+	req, err = http.NewRequest("GET", "/state", nil)
+	if err != nil {
+		return err
+	}
+	if err := resolveURL(t.baseUrl, req); err != nil {
+		return err
+	}
+
+	resp, err = t.sendWithCookies(req, "")
+	if err != nil {
+		return fmt.Errorf("failed to get the CSRF token: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Non-200 response on getting the CSRF token\ncode=%d\nbody=%s", resp.StatusCode, readAndClose(resp))
+	}
+
+	var state struct {
+		CsrfToken string `json:"csrf_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+		return fmt.Errorf("failed to get the CSRF token: %s", err)
+	}
+	t.csrf = state.CsrfToken
+
+	return nil
+}
+
+func (t *sessionTransport) send(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	csrf := t.csrf
+	t.mu.Unlock()
+
+	return t.sendWithCookies(req, csrf)
+}
+
+// sendWithCookies sends the request with the cookies of the session and stores the cookies of the response
+func (t *sessionTransport) sendWithCookies(req *http.Request, csrf string) (*http.Response, error) {
+	req.Header.Del("Cookie")
+	for _, cookie := range t.jar.Cookies(req.URL) {
+		req.AddCookie(cookie)
+	}
+	if csrf != "" {
+		req.Header.Set("Csrf-Token", csrf)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.underlyingTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cookies := resp.Cookies(); len(cookies) > 0 {
+		t.jar.SetCookies(req.URL, cookies)
+	}
+
+	return resp, nil
+}
+
+// WithSessionAuth authenticates the client with the session of an administrator instead of the API token and secret
+func WithSessionAuth(username, password string) ClientOption {
+	return func(o *clientOptions) {
+		o.username = username
+		o.password = password
+	}
+}
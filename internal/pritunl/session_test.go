@@ -0,0 +1,70 @@
+package pritunl_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestSessionAuth(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	apiClient := fake.NewSessionClient()
+
+	if err := apiClient.TestApiCall(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	organization, err := apiClient.CreateOrganization("tfacc-org1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := apiClient.GetOrganization(organization.ID); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if logins := fake.Requests("POST /auth/session"); logins != 1 {
+		t.Fatalf("expected a single login, got %d", logins)
+	}
+
+	fake.ExpireSessions()
+
+	organization.Name = "tfacc-org2"
+	if err := apiClient.UpdateOrganization(organization.ID, organization); err != nil {
+		t.Fatalf("expected the request to be sent again after logging in, got %s", err)
+	}
+	if logins := fake.Requests("POST /auth/session"); logins != 2 {
+		t.Fatalf("expected a login after the session expired, got %d", logins)
+	}
+
+	updated, err := apiClient.GetOrganization(organization.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if updated.Name != "tfacc-org2" {
+		t.Fatalf("expected the organization to be updated, got %s", updated.Name)
+	}
+}
+
+func TestSessionAuthInvalidCredentials(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	apiClient := pritunl.NewClient(fake.URL, "", "", false, pritunl.WithSessionAuth(pritunltest.Username, "invalid"))
+
+	err := apiClient.TestApiCall()
+	if err == nil || !strings.Contains(err.Error(), "the username or the password is invalid") {
+		t.Fatalf("expected an invalid credentials error, got %v", err)
+	}
+}
+
+func TestFakeServerRejectsInvalidToken(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	apiClient := pritunl.NewClient(fake.URL, pritunltest.Token, "invalid", false)
+
+	err := apiClient.TestApiCall()
+	if err == nil || !strings.Contains(err.Error(), "the token or the secret is invalid") {
+		t.Fatalf("expected an invalid credentials error, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package pritunl
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+// TLSOptions configures how the client verifies the Pritunl API and authenticates to it
+type TLSOptions struct {
+	// Insecure disables the verification of the server certificate
+	Insecure bool
+	// CACertPEM contains PEM encoded certificates trusted in addition to the system ones
+	CACertPEM []byte
+	// ClientCertPEM and ClientKeyPEM are used for mutual TLS
+	ClientCertPEM []byte
+	ClientKeyPEM  []byte
+	// ServerName overrides the name the server certificate is verified against
+	ServerName string
+}
+
+// Config builds the TLS configuration of the client
+func (o TLSOptions) Config() (*tls.Config, error) {
+	config := &tls.Config{
+		InsecureSkipVerify: o.Insecure,
+		ServerName:         o.ServerName,
+	}
+
+	if len(o.CACertPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(o.CACertPEM) {
+			return nil, fmt.Errorf("no valid PEM encoded certificates found in the CA certificate")
+		}
+		config.RootCAs = pool
+	}
+
+	if len(o.ClientCertPEM) > 0 || len(o.ClientKeyPEM) > 0 {
+		if len(o.ClientCertPEM) == 0 || len(o.ClientKeyPEM) == 0 {
+			return nil, fmt.Errorf("both the client certificate and the client key are required for mutual TLS")
+		}
+
+		certificate, err := tls.X509KeyPair(o.ClientCertPEM, o.ClientKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %s", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
+
+// WithTLSConfig replaces the TLS configuration built from the insecure argument of NewClient
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(o *clientOptions) {
+		o.tlsConfig = config
+	}
+}
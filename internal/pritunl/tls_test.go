@@ -0,0 +1,222 @@
+package pritunl
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	certPEM     []byte
+	keyPEM      []byte
+}
+
+func TestTLSOptions(t *testing.T) {
+	ca := newTestCertificate(t, nil, func(template *x509.Certificate) {
+		template.IsCA = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		template.BasicConstraintsValid = true
+	})
+	serverCert := newTestCertificate(t, ca, func(template *x509.Certificate) {
+		template.DNSNames = []string{"pritunl.internal"}
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	})
+	clientCert := newTestCertificate(t, ca, func(template *x509.Certificate) {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	})
+
+	newServer := func(clientAuth tls.ClientAuthType) *httptest.Server {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("{}"))
+		}))
+		server.TLS = &tls.Config{
+			Certificates: []tls.Certificate{serverCert.tlsCertificate(t)},
+			ClientAuth:   clientAuth,
+			ClientCAs:    ca.pool(),
+		}
+		server.StartTLS()
+		return server
+	}
+
+	testCases := []struct {
+		name        string
+		clientAuth  tls.ClientAuthType
+		options     TLSOptions
+		expectedErr string
+	}{
+		{
+			name:        "unknown CA",
+			options:     TLSOptions{ServerName: "pritunl.internal"},
+			expectedErr: "certificate signed by unknown authority",
+		},
+		{
+			name:    "custom CA",
+			options: TLSOptions{CACertPEM: ca.certPEM, ServerName: "pritunl.internal"},
+		},
+		{
+			name:        "server name not matching the certificate",
+			options:     TLSOptions{CACertPEM: ca.certPEM},
+			expectedErr: "127.0.0.1",
+		},
+		{
+			name:    "insecure",
+			options: TLSOptions{Insecure: true},
+		},
+		{
+			name:       "client certificate",
+			clientAuth: tls.RequireAndVerifyClientCert,
+			options: TLSOptions{
+				CACertPEM:     ca.certPEM,
+				ServerName:    "pritunl.internal",
+				ClientCertPEM: clientCert.certPEM,
+				ClientKeyPEM:  clientCert.keyPEM,
+			},
+		},
+		{
+			name:        "missing client certificate",
+			clientAuth:  tls.RequireAndVerifyClientCert,
+			options:     TLSOptions{CACertPEM: ca.certPEM, ServerName: "pritunl.internal"},
+			expectedErr: "certificate required",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newServer(tc.clientAuth)
+			defer server.Close()
+
+			config, err := tc.options.Config()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			apiClient := NewClient(server.URL, "token", "secret", false, WithTLSConfig(config))
+			err = apiClient.TestApiCall()
+
+			if tc.expectedErr == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tc.expectedErr != "" && (err == nil || !strings.Contains(err.Error(), tc.expectedErr)) {
+				t.Fatalf("expected error containing %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestTLSOptionsInvalid(t *testing.T) {
+	ca := newTestCertificate(t, nil, func(template *x509.Certificate) {
+		template.IsCA = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		template.BasicConstraintsValid = true
+	})
+
+	testCases := []struct {
+		name        string
+		options     TLSOptions
+		expectedErr string
+	}{
+		{
+			name:        "invalid CA certificate",
+			options:     TLSOptions{CACertPEM: []byte("not a certificate")},
+			expectedErr: "no valid PEM encoded certificates",
+		},
+		{
+			name:        "client certificate without the key",
+			options:     TLSOptions{ClientCertPEM: ca.certPEM},
+			expectedErr: "both the client certificate and the client key are required",
+		},
+		{
+			name:        "client key not matching the certificate",
+			options:     TLSOptions{ClientCertPEM: ca.certPEM, ClientKeyPEM: []byte("not a key")},
+			expectedErr: "invalid client certificate",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.options.Config()
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+// newTestCertificate generates a certificate signed by the parent, or a self-signed one without the parent
+func newTestCertificate(t *testing.T, parent *testCertificate, customize func(template *x509.Certificate)) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: "terraform-provider-pritunl test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	customize(template)
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.certificate, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCertificate{
+		certificate: certificate,
+		key:         key,
+		certPEM:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:      pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func (c *testCertificate) tlsCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	certificate, err := tls.X509KeyPair(c.certPEM, c.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return certificate
+}
+
+func (c *testCertificate) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(c.certificate)
+
+	return pool
+}
@@ -2,54 +2,217 @@ package pritunl
 
 import (
 	"crypto/hmac"
-	"crypto/md5"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+// clockSkewTolerance is the difference between the local clock and the Date header of the API
+// which is not corrected, the header has a resolution of a second
+const clockSkewTolerance = 5 * time.Second
+
 type transport struct {
 	underlyingTransport http.RoundTripper
 	apiToken            string
 	apiSecret           string
 	baseUrl             string
+
+	// now and nonce can be replaced in tests, time.Now and randomNonce are used by default
+	now   func() time.Time
+	nonce func() (string, error)
+
+	mu sync.Mutex
+	// clockOffset is added to the local time of the signatures, it's measured from the Date header of the API
+	clockOffset time.Duration
+}
+
+// AuthenticationError is returned when the API rejects the signature of a request
+type AuthenticationError struct {
+	Method string
+	Path   string
+	Body   string
+	// ClockSkew is the difference between the clock of the API and the local clock, when the API sent its Date
+	ClockSkew      time.Duration
+	ClockSkewKnown bool
+	// Corrected reports the request was rejected again after it was signed with the corrected clock
+	Corrected bool
+}
+
+func (e *AuthenticationError) Error() string {
+	var reason string
+	switch {
+	case e.Corrected:
+		reason = fmt.Sprintf("the request was rejected after correcting the local clock skew of %s, the token or the secret is invalid", e.ClockSkew)
+	case e.ClockSkewKnown:
+		reason = "the token or the secret is invalid"
+	default:
+		reason = "the token or the secret is invalid, or the local clock is out of sync with the Pritunl server"
+	}
+
+	return fmt.Sprintf("unauthorized: %s %s: %s\nbody=%s", e.Method, e.Path, reason, e.Body)
 }
 
 func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
-	if req.URL.Host == "" {
-		u, err := url.Parse(t.baseUrl)
+	// the request must not be modified by the transport, it can be sent again on retries
+	req = req.Clone(req.Context())
+
+	if err := resolveURL(t.baseUrl, req); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	skew, skewKnown := t.clockSkew(resp)
+	authErr := &AuthenticationError{
+		Method:         req.Method,
+		Path:           req.URL.Path,
+		Body:           readAndClose(resp),
+		ClockSkew:      skew,
+		ClockSkewKnown: skewKnown,
+	}
+
+	// the signature is sent once more only when the rejection can be explained by the clock
+	if !skewKnown || !t.correctClock(skew) || (req.Body != nil && req.GetBody == nil) {
+		return nil, authErr
+	}
+	log.Printf("[WARN] pritunl: %s %s was rejected and the local clock is %s off the Pritunl server, retrying with the corrected clock", req.Method, req.URL.Path, skew)
+
+	if req.Body != nil {
+		body, err := req.GetBody()
 		if err != nil {
 			return nil, err
 		}
+		req.Body = body
+	}
 
-		u.Path = path.Join(u.Path, req.URL.Path)
-		req.URL = u
+	resp, err = t.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
 	}
 
-	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
-	timestampNano := strconv.FormatInt(time.Now().UnixNano(), 10)
+	authErr.Body = readAndClose(resp)
+	authErr.Corrected = true
 
-	nonceMac := hmac.New(md5.New, []byte(t.apiSecret))
-	nonceMac.Write([]byte(strings.Join([]string{timestampNano, req.URL.Path, t.apiToken}, "")))
-	nonce := fmt.Sprintf("%x", nonceMac.Sum(nil))
-	authString := strings.Join([]string{t.apiToken, timestamp, nonce, strings.ToUpper(req.Method), req.URL.Path}, "&")
+	return nil, authErr
+}
 
-	mac := hmac.New(sha256.New, []byte(t.apiSecret))
-	mac.Write([]byte(authString))
-	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+// resolveURL joins the relative URL of the request with the base URL of the API
+func resolveURL(baseUrl string, req *http.Request) error {
+	if req.URL.Host != "" {
+		return nil
+	}
+
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return err
+	}
+
+	u.Path = path.Join(u.Path, req.URL.Path)
+	u.RawQuery = req.URL.RawQuery
+	req.URL = u
+
+	return nil
+}
+
+// send signs the request with the corrected clock and sends it
+func (t *transport) send(req *http.Request) (*http.Response, error) {
+	nonce, err := t.newNonce()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the nonce: %s", err)
+	}
 
-	req.Header.Add("Auth-Token", t.apiToken)
-	req.Header.Add("Auth-Timestamp", timestamp)
-	req.Header.Add("Auth-Nonce", nonce)
-	req.Header.Add("Auth-Signature", signature)
+	t.mu.Lock()
+	timestamp := strconv.FormatInt(t.currentTime().Add(t.clockOffset).Unix(), 10)
+	t.mu.Unlock()
 
-	req.Header.Add("Content-Type", "application/json")
+	req.Header.Set("Auth-Token", t.apiToken)
+	req.Header.Set("Auth-Timestamp", timestamp)
+	req.Header.Set("Auth-Nonce", nonce)
+	req.Header.Set("Auth-Signature", signature(t.apiToken, t.apiSecret, timestamp, nonce, req.Method, req.URL.Path))
+
+	req.Header.Set("Content-Type", "application/json")
 
 	return t.underlyingTransport.RoundTrip(req)
 }
+
+// clockSkew measures the difference between the clock of the API and the local clock from the Date header of the response
+func (t *transport) clockSkew(resp *http.Response) (time.Duration, bool) {
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, false
+	}
+
+	return date.Sub(t.currentTime()).Round(time.Second), true
+}
+
+// correctClock stores the clock skew and reports whether it changes the signatures
+func (t *transport) correctClock(skew time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	difference := skew - t.clockOffset
+	if difference < 0 {
+		difference = -difference
+	}
+	if difference < clockSkewTolerance {
+		return false
+	}
+
+	t.clockOffset = skew
+	return true
+}
+
+func (t *transport) currentTime() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+func (t *transport) newNonce() (string, error) {
+	if t.nonce != nil {
+		return t.nonce()
+	}
+	return randomNonce()
+}
+
+// randomNonce generates 128 random bits encoded as 32 hex characters
+func randomNonce() (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(nonce), nil
+}
+
+// signature signs the request the way the Pritunl API expects it
+func signature(apiToken, apiSecret, timestamp, nonce, method, path string) string {
+	authString := strings.Join([]string{apiToken, timestamp, nonce, strings.ToUpper(method), path}, "&")
+
+	mac := hmac.New(sha256.New, []byte(apiSecret))
+	mac.Write([]byte(authString))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func readAndClose(resp *http.Response) string {
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
@@ -0,0 +1,275 @@
+package pritunl
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSignature(t *testing.T) {
+	testCases := []struct {
+		name      string
+		token     string
+		secret    string
+		timestamp string
+		nonce     string
+		method    string
+		path      string
+		expected  string
+	}{
+		{
+			name:      "state",
+			token:     "token",
+			secret:    "secret",
+			timestamp: "1700000000",
+			nonce:     "0123456789abcdef0123456789abcdef",
+			method:    "GET",
+			path:      "/state",
+			expected:  "OUoW9BAQeNyRnLyK8fx4Zc9Of6YF9lNfWjwqWo7dDi4=",
+		},
+		{
+			name:      "lowercase method",
+			token:     "pDA4s4Eh0jBMxAEL3AZ4b0RDkwPMyKJ2",
+			secret:    "Zqcn6r1BEWkKlE1I2Tw0UnbmIDLdFEYb",
+			timestamp: "1577836800",
+			nonce:     "00000000000000000000000000000000",
+			method:    "put",
+			path:      "/server/5f3e2b1c4d9a8e7f6b5c4d3e/operation/start",
+			expected:  "IyS0cnEz/06S9k0/ffKrbLkQgP0cl0900ehsFc5hqTI=",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := signature(tc.token, tc.secret, tc.timestamp, tc.nonce, tc.method, tc.path)
+			if actual != tc.expected {
+				t.Fatalf("expected signature %s, got %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRandomNonce(t *testing.T) {
+	format := regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		nonce, err := randomNonce()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !format.MatchString(nonce) {
+			t.Fatalf("expected 32 hex characters, got %q", nonce)
+		}
+		if seen[nonce] {
+			t.Fatalf("nonce %s generated twice", nonce)
+		}
+		seen[nonce] = true
+	}
+}
+
+func TestTransportSignsRequests(t *testing.T) {
+	var headers http.Header
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		path = r.URL.Path
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Transport: &transport{
+		underlyingTransport: http.DefaultTransport,
+		apiToken:            "token",
+		apiSecret:           "secret",
+		baseUrl:             server.URL,
+		now:                 func() time.Time { return time.Unix(1700000000, 0) },
+		nonce:               func() (string, error) { return "0123456789abcdef0123456789abcdef", nil },
+	}}
+
+	req, _ := http.NewRequest("GET", "/state", nil)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	expected := map[string]string{
+		"Auth-Token":     "token",
+		"Auth-Timestamp": "1700000000",
+		"Auth-Nonce":     "0123456789abcdef0123456789abcdef",
+		"Auth-Signature": "OUoW9BAQeNyRnLyK8fx4Zc9Of6YF9lNfWjwqWo7dDi4=",
+		"Content-Type":   "application/json",
+	}
+	for name, value := range expected {
+		if headers.Get(name) != value {
+			t.Fatalf("expected %s %q, got %q", name, value, headers.Get(name))
+		}
+	}
+	if path != "/state" {
+		t.Fatalf("expected path /state, got %s", path)
+	}
+}
+
+func TestTransportClockSkew(t *testing.T) {
+	const serverSkew = time.Hour
+
+	testCases := []struct {
+		name              string
+		validCredentials  bool
+		sendDate          bool
+		body              string
+		expectedRequests  int32
+		expectedCorrected bool
+		expectedKnown     bool
+	}{
+		{
+			name:             "skew corrected",
+			validCredentials: true,
+			sendDate:         true,
+			expectedRequests: 2,
+		},
+		{
+			name:             "skew corrected with a body",
+			validCredentials: true,
+			sendDate:         true,
+			body:             `{"name":"org"}`,
+			expectedRequests: 2,
+		},
+		{
+			name:              "invalid credentials after the correction",
+			sendDate:          true,
+			expectedRequests:  2,
+			expectedCorrected: true,
+			expectedKnown:     true,
+		},
+		{
+			name:             "skew unknown without the date",
+			validCredentials: true,
+			expectedRequests: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				serverTime := time.Now().Add(serverSkew)
+
+				if tc.sendDate {
+					w.Header().Set("Date", serverTime.UTC().Format(http.TimeFormat))
+				} else {
+					w.Header()["Date"] = nil
+				}
+
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != tc.body {
+					t.Errorf("expected body %q, got %q", tc.body, body)
+				}
+
+				if !tc.validCredentials || !validTimestamp(r.Header.Get("Auth-Timestamp"), serverTime) {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+			}))
+			defer server.Close()
+
+			apiClient := NewClient(server.URL, "token", "secret", false)
+			httpClient := apiClient.(*client).httpClient
+
+			do := func() error {
+				var req *http.Request
+				if tc.body != "" {
+					req, _ = http.NewRequest("POST", "/organization", strings.NewReader(tc.body))
+				} else {
+					req, _ = http.NewRequest("GET", "/state", nil)
+				}
+
+				resp, err := httpClient.Do(req)
+				if err != nil {
+					return err
+				}
+				resp.Body.Close()
+				return nil
+			}
+
+			err := do()
+			if requests.Load() != tc.expectedRequests {
+				t.Fatalf("expected %d requests, got %d", tc.expectedRequests, requests.Load())
+			}
+
+			if tc.validCredentials && tc.sendDate {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				// the following requests are signed with the corrected clock
+				if err := do(); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if requests.Load() != tc.expectedRequests+1 {
+					t.Fatalf("expected the corrected clock to be reused, got %d requests", requests.Load())
+				}
+				return
+			}
+
+			var authErr *AuthenticationError
+			if !errors.As(err, &authErr) {
+				t.Fatalf("expected an AuthenticationError, got %v", err)
+			}
+			if authErr.Corrected != tc.expectedCorrected || authErr.ClockSkewKnown != tc.expectedKnown {
+				t.Fatalf("expected corrected=%t and known skew=%t, got %+v", tc.expectedCorrected, tc.expectedKnown, authErr)
+			}
+		})
+	}
+}
+
+func TestAuthenticationError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      AuthenticationError
+		expected string
+	}{
+		{
+			name:     "invalid credentials",
+			err:      AuthenticationError{ClockSkewKnown: true},
+			expected: "the token or the secret is invalid",
+		},
+		{
+			name:     "rejected after the correction",
+			err:      AuthenticationError{ClockSkew: time.Hour, ClockSkewKnown: true, Corrected: true},
+			expected: "after correcting the local clock skew of 1h0m0s",
+		},
+		{
+			name:     "unknown skew",
+			err:      AuthenticationError{},
+			expected: "or the local clock is out of sync with the Pritunl server",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !strings.Contains(tc.err.Error(), tc.expected) {
+				t.Fatalf("expected error containing %q, got %q", tc.expected, tc.err.Error())
+			}
+		})
+	}
+}
+
+// validTimestamp reports whether the signature timestamp is close enough to the server time
+func validTimestamp(timestamp string, serverTime time.Time) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	difference := serverTime.Sub(time.Unix(seconds, 0))
+	return difference > -30*time.Second && difference < 30*time.Second
+}
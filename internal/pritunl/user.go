@@ -24,12 +24,22 @@ type User struct {
 	SSO             interface{}              `json:"sso,omitempty"`
 	BypassSecondary bool                     `json:"bypass_secondary,omitempty"`
 	Groups          []string                 `json:"groups,omitempty"`
-	Audit           bool                     `json:"audit,omitempty"`
+	Audit           bool                     `json:"audit"`
 	Gravatar        bool                     `json:"gravatar,omitempty"`
 	OtpAuth         bool                     `json:"otp_auth,omitempty"`
 	DeviceAuth      bool                     `json:"device_auth,omitempty"`
 	Organization    string                   `json:"organization,omitempty"`
 	Pin             *Pin                      `json:"pin,omitempty"`
+	Servers         []UserServer             `json:"servers,omitempty"`
+}
+
+// AuditEvent is an event recorded for a user with auditing enabled, the timestamp is a Unix timestamp
+type AuditEvent struct {
+	ID         string `json:"id"`
+	Timestamp  int64  `json:"timestamp"`
+	Type       string `json:"type"`
+	RemoteAddr string `json:"remote_addr"`
+	Message    string `json:"message"`
 }
 
 type PortForwarding struct {
@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const defaultProfile = "default"
+
+// providerCredentials are the values of the provider which can come from a profile or the credential process
+type providerCredentials struct {
+	URL    string `json:"url"`
+	Token  string `json:"token"`
+	Secret string `json:"secret"`
+}
+
+func (c providerCredentials) complete() bool {
+	return c.URL != "" && c.Token != "" && c.Secret != ""
+}
+
+// fill sets the empty values from the other credentials
+func (c *providerCredentials) fill(other providerCredentials) {
+	if c.URL == "" {
+		c.URL = other.URL
+	}
+	if c.Token == "" {
+		c.Token = other.Token
+	}
+	if c.Secret == "" {
+		c.Secret = other.Secret
+	}
+}
+
+// defaultCredentialsFile returns ~/.config/pritunl/credentials
+func defaultCredentialsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".config", "pritunl", "credentials")
+}
+
+// resolveCredentials completes the credentials set with the provider attributes or the environment variables.
+// The missing values are taken from the output of the credential process first and then from the profile,
+// the credential process can also be set in the profile. The default profile is used only when it exists.
+func resolveCredentials(ctx context.Context, explicit providerCredentials, credentialProcess, profile, credentialsFile string) (providerCredentials, error) {
+	result := explicit
+	if result.complete() {
+		return result, nil
+	}
+
+	profileValues, err := readProfile(credentialsFile, profile)
+	if err != nil {
+		return result, err
+	}
+
+	if credentialProcess == "" {
+		credentialProcess = profileValues["credential_process"]
+	}
+	if credentialProcess != "" {
+		processCredentials, err := runCredentialProcess(ctx, credentialProcess)
+		if err != nil {
+			return result, err
+		}
+		result.fill(processCredentials)
+	}
+
+	result.fill(providerCredentials{
+		URL:    profileValues["url"],
+		Token:  profileValues["token"],
+		Secret: profileValues["secret"],
+	})
+
+	return result, nil
+}
+
+// readProfile reads the values of the profile from the credentials file.
+// A missing file or profile is an error only when the profile is set explicitly.
+func readProfile(credentialsFile, profile string) (map[string]string, error) {
+	explicit := profile != ""
+	if !explicit {
+		profile = defaultProfile
+	}
+	if credentialsFile == "" {
+		if explicit {
+			return nil, fmt.Errorf("profile %q is set, but the credentials file is unknown", profile)
+		}
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(credentialsFile)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the credentials file: %s", err)
+	}
+
+	profiles, err := parseCredentialsFile(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials file %s: %s", credentialsFile, err)
+	}
+
+	values, ok := profiles[profile]
+	if !ok && explicit {
+		return nil, fmt.Errorf("profile %q not found in the credentials file %s", profile, credentialsFile)
+	}
+
+	return values, nil
+}
+
+// parseCredentialsFile parses the INI formatted profiles, e.g.
+//
+//	[production]
+//	url    = https://vpn.example.com
+//	token  = ...
+//	secret = ...
+func parseCredentialsFile(content []byte) (map[string]map[string]string, error) {
+	profiles := make(map[string]map[string]string)
+
+	var current map[string]string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty profile name", lineNumber)
+			}
+			if _, ok := profiles[name]; !ok {
+				profiles[name] = make(map[string]string)
+			}
+			current = profiles[name]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected a profile or a key = value pair", lineNumber)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: the key is not in a profile", lineNumber)
+		}
+		current[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return profiles, scanner.Err()
+}
+
+// runCredentialProcess runs the command with the shell and decodes the credentials from its JSON output
+func runCredentialProcess(ctx context.Context, command string) (providerCredentials, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	var credentials providerCredentials
+	if err := cmd.Run(); err != nil {
+		return credentials, fmt.Errorf("credential_process failed: %s\nstderr=%s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &credentials); err != nil {
+		return credentials, fmt.Errorf("credential_process returned invalid JSON: %s", err)
+	}
+
+	return credentials, nil
+}
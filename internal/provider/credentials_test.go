@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const testCredentialsFile = `
+# shared profiles
+[default]
+url    = https://default.example.com
+token  = default-token
+secret = default-secret
+
+[production]
+url = https://production.example.com
+token = production-token
+secret = production-secret
+
+; the secret comes from the process
+[vault]
+url                = https://vault.example.com
+token              = vault-profile-token
+credential_process = printf '{"token": "vault-token", "secret": "vault-secret"}'
+`
+
+func TestParseCredentialsFile(t *testing.T) {
+	profiles, err := parseCredentialsFile([]byte(testCredentialsFile))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(profiles) != 3 {
+		t.Fatalf("expected 3 profiles, got %v", profiles)
+	}
+	if profiles["production"]["token"] != "production-token" {
+		t.Fatalf("expected the production token, got %q", profiles["production"]["token"])
+	}
+	if profiles["vault"]["credential_process"] != `printf '{"token": "vault-token", "secret": "vault-secret"}'` {
+		t.Fatalf("expected the value after the first =, got %q", profiles["vault"]["credential_process"])
+	}
+
+	for _, invalid := range []string{"url = https://example.com", "[default]\nurl", "[]"} {
+		if _, err := parseCredentialsFile([]byte(invalid)); err == nil {
+			t.Fatalf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestResolveCredentials(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the credential processes of the test use sh")
+	}
+
+	credentialsFile := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(credentialsFile, []byte(testCredentialsFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+	missingFile := filepath.Join(t.TempDir(), "missing")
+
+	testCases := []struct {
+		name              string
+		explicit          providerCredentials
+		credentialProcess string
+		profile           string
+		credentialsFile   string
+		expected          providerCredentials
+		expectedErr       string
+	}{
+		{
+			name:            "default profile",
+			credentialsFile: credentialsFile,
+			expected:        providerCredentials{URL: "https://default.example.com", Token: "default-token", Secret: "default-secret"},
+		},
+		{
+			name:            "named profile",
+			profile:         "production",
+			credentialsFile: credentialsFile,
+			expected:        providerCredentials{URL: "https://production.example.com", Token: "production-token", Secret: "production-secret"},
+		},
+		{
+			name:            "attributes override the profile",
+			explicit:        providerCredentials{Token: "explicit-token"},
+			profile:         "production",
+			credentialsFile: credentialsFile,
+			expected:        providerCredentials{URL: "https://production.example.com", Token: "explicit-token", Secret: "production-secret"},
+		},
+		{
+			name:              "credential process overrides the profile",
+			credentialProcess: `printf '{"secret": "process-secret"}'`,
+			profile:           "production",
+			credentialsFile:   credentialsFile,
+			expected:          providerCredentials{URL: "https://production.example.com", Token: "production-token", Secret: "process-secret"},
+		},
+		{
+			name:              "attributes override the credential process",
+			explicit:          providerCredentials{URL: "https://explicit.example.com"},
+			credentialProcess: `printf '{"url": "https://process.example.com", "token": "process-token", "secret": "process-secret"}'`,
+			credentialsFile:   missingFile,
+			expected:          providerCredentials{URL: "https://explicit.example.com", Token: "process-token", Secret: "process-secret"},
+		},
+		{
+			name:            "credential process of the profile",
+			profile:         "vault",
+			credentialsFile: credentialsFile,
+			expected:        providerCredentials{URL: "https://vault.example.com", Token: "vault-token", Secret: "vault-secret"},
+		},
+		{
+			name:              "credential process not run with complete attributes",
+			explicit:          providerCredentials{URL: "https://explicit.example.com", Token: "token", Secret: "secret"},
+			credentialProcess: "exit 1",
+			expected:          providerCredentials{URL: "https://explicit.example.com", Token: "token", Secret: "secret"},
+		},
+		{
+			name:            "missing default profile",
+			explicit:        providerCredentials{URL: "https://explicit.example.com"},
+			credentialsFile: missingFile,
+			expected:        providerCredentials{URL: "https://explicit.example.com"},
+		},
+		{
+			name:            "missing named profile",
+			profile:         "staging",
+			credentialsFile: credentialsFile,
+			expectedErr:     `profile "staging" not found`,
+		},
+		{
+			name:            "missing credentials file of the named profile",
+			profile:         "production",
+			credentialsFile: missingFile,
+			expectedErr:     "failed to read the credentials file",
+		},
+		{
+			name:              "failed credential process",
+			credentialProcess: "echo 'vault is sealed' >&2; exit 3",
+			credentialsFile:   missingFile,
+			expectedErr:       "vault is sealed",
+		},
+		{
+			name:              "invalid output of the credential process",
+			credentialProcess: "echo token",
+			credentialsFile:   missingFile,
+			expectedErr:       "credential_process returned invalid JSON",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := resolveCredentials(context.Background(), tc.explicit, tc.credentialProcess, tc.profile, tc.credentialsFile)
+
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if actual != tc.expected {
+				t.Fatalf("expected %+v, got %+v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+func dataSourceAvailableNetwork() *schema.Resource {
+	return &schema.Resource{
+		Description: "Use this data source to get the next network of the pool which isn't used by any Pritunl server.",
+		ReadContext: dataSourceAvailableNetworkRead,
+		Schema: map[string]*schema.Schema{
+			"network_pool": {
+				Description:  "Network pool to look for an available network in",
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateCIDRNetwork,
+			},
+			"network_prefix_length": {
+				Description:  "Prefix length of the network to look for",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      24,
+				ValidateFunc: validation.IntBetween(8, 30),
+			},
+			"network": {
+				Description: "The first network of the pool which doesn't overlap networks of existing servers",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceAvailableNetworkRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	servers, err := apiClient.GetServers()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	network, err := findAvailableNetwork(
+		d.Get("network_pool").(string),
+		d.Get("network_prefix_length").(int),
+		usedServerNetworks(servers, ""),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(network)
+	_ = d.Set("network", network)
+
+	return nil
+}
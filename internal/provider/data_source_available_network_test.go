@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceAvailableNetwork(t *testing.T) {
+	pool := "10.231.0.0/16"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testPritunlServerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPritunlAvailableNetworkConfig(pool),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pritunl_server.test", "network", "10.231.0.0/24"),
+					resource.TestCheckResourceAttr("data.pritunl_available_network.test", "network", "10.231.1.0/24"),
+				),
+			},
+			{
+				// the allocated network must stay the same on the next plans
+				Config:   testPritunlAvailableNetworkConfig(pool),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testPritunlAvailableNetworkConfig(pool string) string {
+	return fmt.Sprintf(`
+resource "pritunl_server" "test" {
+	name         = "tfacc-server-pool"
+	network_pool = "%[1]s"
+}
+
+data "pritunl_available_network" "test" {
+	network_pool = "%[1]s"
+
+	depends_on = [pritunl_server.test]
+}
+`, pool)
+}
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -82,14 +83,9 @@ func dataSourceHost() *schema.Resource {
 }
 
 func dataSourceHostRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	hostname := d.Get("hostname")
-	filterFunction := func(host pritunl.Host) bool {
-		return host.Hostname == hostname
-	}
-
-	host, err := filterHosts(meta, filterFunction)
+	host, err := findHostByHostname(meta, d.Get("hostname").(string))
 	if err != nil {
-		return diag.Errorf("could not find host with a hostname %s. Previous error message: %v", hostname, err)
+		return diag.FromErr(err)
 	}
 
 	d.SetId(host.ID)
@@ -109,6 +105,18 @@ func dataSourceHostRead(_ context.Context, d *schema.ResourceData, meta interfac
 	return nil
 }
 
+// findHostByHostname returns the host with the hostname, the lookup of the host data sources and resources
+func findHostByHostname(meta interface{}, hostname string) (pritunl.Host, error) {
+	host, err := filterHosts(meta, func(host pritunl.Host) bool {
+		return host.Hostname == hostname
+	})
+	if err != nil {
+		return pritunl.Host{}, fmt.Errorf("could not find host with a hostname %s. Previous error message: %v", hostname, err)
+	}
+
+	return host, nil
+}
+
 func filterHosts(meta interface{}, test func(host pritunl.Host) bool) (pritunl.Host, error) {
 	apiClient := meta.(pritunl.Client)
 
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+func dataSourceHostUsage() *schema.Resource {
+	usageSeries := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"timestamp": {
+				Description: "Unix timestamp of the sample",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"percent": {
+				Description: "Usage in percent",
+				Type:        schema.TypeFloat,
+				Computed:    true,
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Description: "Use this data source to get the CPU and memory usage of a Pritunl host over a period.",
+		ReadContext: dataSourceHostUsageRead,
+		Schema: map[string]*schema.Schema{
+			"host_id": {
+				Description:  "ID of the host",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"host_id", "hostname"},
+			},
+			"hostname": {
+				Description:  "Hostname of the host",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"host_id", "hostname"},
+			},
+			"period": {
+				Description:  fmt.Sprintf("Period of the series, one of %v. The longer the period, the longer the interval between the samples", pritunl.MetricPeriods),
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "1d",
+				ValidateFunc: validation.StringInSlice(pritunl.MetricPeriods, false),
+			},
+			"cpu": {
+				Description: "CPU usage of the host, the oldest sample first",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        usageSeries,
+			},
+			"memory": {
+				Description: "Memory usage of the host, the oldest sample first",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        usageSeries,
+			},
+			"latest_timestamp": {
+				Description: "Unix timestamp of the latest sample, 0 without samples",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"latest_cpu": {
+				Description: "CPU usage in percent of the latest sample",
+				Type:        schema.TypeFloat,
+				Computed:    true,
+			},
+			"latest_memory": {
+				Description: "Memory usage in percent of the latest sample",
+				Type:        schema.TypeFloat,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceHostUsageRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	var host pritunl.Host
+	if hostname, ok := d.GetOk("hostname"); ok {
+		found, err := findHostByHostname(meta, hostname.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		host = found
+	} else {
+		found, err := apiClient.GetClusterHost(d.Get("host_id").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		host = *found
+	}
+	hostId := host.ID
+
+	period := d.Get("period").(string)
+	usage, err := apiClient.GetHostUsage(hostId, period)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = d.Set("cpu", flattenUsageSeries(usage.CPU)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = d.Set("memory", flattenUsageSeries(usage.Memory)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	var latestTimestamp int64
+	var latestCPU, latestMemory float64
+	if len(usage.CPU) > 0 {
+		latest := usage.CPU[len(usage.CPU)-1]
+		latestTimestamp, latestCPU = latest.Timestamp, latest.Value
+	}
+	if len(usage.Memory) > 0 {
+		latest := usage.Memory[len(usage.Memory)-1]
+		if latest.Timestamp > latestTimestamp {
+			latestTimestamp = latest.Timestamp
+		}
+		latestMemory = latest.Value
+	}
+	d.Set("latest_timestamp", int(latestTimestamp))
+	d.Set("latest_cpu", latestCPU)
+	d.Set("latest_memory", latestMemory)
+
+	d.Set("host_id", hostId)
+	d.Set("hostname", host.Hostname)
+	d.SetId(fmt.Sprintf("%s-%s", hostId, period))
+
+	return nil
+}
+
+func flattenUsageSeries(points []pritunl.UsagePoint) []interface{} {
+	series := make([]interface{}, 0, len(points))
+	for _, point := range points {
+		series = append(series, map[string]interface{}{
+			"timestamp": int(point.Timestamp),
+			"percent":   point.Value,
+		})
+	}
+
+	return series
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestDataSourceHostUsageFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	host := fake.AddHost(pritunl.Host{Hostname: "node-1.internal", Status: "online"})
+	fake.SetHostUsage(host.ID, "5m", pritunl.HostUsage{
+		CPU:    []pritunl.UsagePoint{{Timestamp: 1700000000, Value: 12.5}, {Timestamp: 1700000300, Value: 80.25}},
+		Memory: []pritunl.UsagePoint{{Timestamp: 1700000000, Value: 40}, {Timestamp: 1700000300, Value: 42.5}},
+	})
+
+	t.Run("looks up the host by hostname", func(t *testing.T) {
+		d := applyTestResourceData(t, dataSourceHostUsage(), map[string]interface{}{
+			"hostname": "node-1.internal",
+			"period":   "5m",
+		}, dataSourceHostUsageRead, fake.NewClient())
+
+		if d.Get("host_id").(string) != host.ID {
+			t.Fatalf("expected the host %s, got %s", host.ID, d.Get("host_id"))
+		}
+		if d.Get("cpu.#").(int) != 2 || d.Get("cpu.0.percent").(float64) != 12.5 || d.Get("memory.1.timestamp").(int) != 1700000300 {
+			t.Fatalf("expected the series, got %v and %v", d.Get("cpu"), d.Get("memory"))
+		}
+		if d.Get("latest_timestamp").(int) != 1700000300 || d.Get("latest_cpu").(float64) != 80.25 || d.Get("latest_memory").(float64) != 42.5 {
+			t.Fatalf("expected the latest sample, got %d, %f and %f", d.Get("latest_timestamp"), d.Get("latest_cpu"), d.Get("latest_memory"))
+		}
+	})
+
+	t.Run("reads the host by ID", func(t *testing.T) {
+		d := applyTestResourceData(t, dataSourceHostUsage(), map[string]interface{}{
+			"host_id": host.ID,
+		}, dataSourceHostUsageRead, fake.NewClient())
+
+		if d.Get("hostname").(string) != "node-1.internal" {
+			t.Fatalf("expected the hostname to be read, got %s", d.Get("hostname"))
+		}
+		if d.Get("cpu.#").(int) != 0 || d.Get("latest_timestamp").(int) != 0 {
+			t.Fatalf("expected no samples for the default period, got %v", d.Get("cpu"))
+		}
+	})
+
+	t.Run("fails for an unknown hostname", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, dataSourceHostUsage().Schema, map[string]interface{}{
+			"hostname": "node-2.internal",
+		})
+
+		diags := dataSourceHostUsageRead(context.Background(), d, fake.NewClient())
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, "could not find host with a hostname node-2.internal") {
+			t.Fatalf("expected a missing host error, got %v", diags)
+		}
+	})
+}
@@ -2,8 +2,12 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
 )
 
@@ -12,6 +16,40 @@ func dataSourceHosts() *schema.Resource {
 		Description: "Use this data source to get a list of the Pritunl hosts.",
 		ReadContext: dataSourceHostsRead,
 		Schema: map[string]*schema.Schema{
+			"status": {
+				Description:  "Return only the hosts with the status, online or offline",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"online", "offline"}, false),
+			},
+			"availability_group": {
+				Description: "Return only the hosts in the availability group",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"hostname_regex": {
+				Description:  "Return only the hosts with a hostname matching the regular expression",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"server_id": {
+				Description: "Return only the hosts attached to the server",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"ids": {
+				Description: "IDs of the returned hosts",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"online_ids": {
+				Description: "IDs of the returned hosts which are online",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 			"hosts": {
 				Description: "A list of the Pritunl hosts resources.",
 				Type:        schema.TypeList,
@@ -32,21 +70,92 @@ func dataSourceHostsRead(_ context.Context, d *schema.ResourceData, meta interfa
 		return diag.Errorf("could not find any host. Previous error message: %v", err)
 	}
 
-	var resultHosts []interface{}
+	filters, err := hostsFilters(d, apiClient)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resultHosts := make([]interface{}, 0)
+	ids := make([]string, 0)
+	onlineIds := make([]string, 0)
 
 	for _, host := range hosts {
+		if !matchHost(host, filters) {
+			continue
+		}
+
 		resultHosts = append(resultHosts, flattenHost(&host))
+		ids = append(ids, host.ID)
+		if host.Status == "online" {
+			onlineIds = append(onlineIds, host.ID)
+		}
 	}
 
 	if err = d.Set("hosts", resultHosts); err != nil {
 		return diag.FromErr(err)
 	}
+	d.Set("ids", ids)
+	d.Set("online_ids", onlineIds)
 
 	d.SetId("hosts")
 
 	return nil
 }
 
+// hostsFilters builds the tests of the configured filters, all of them must match
+func hostsFilters(d *schema.ResourceData, apiClient pritunl.Client) ([]func(host pritunl.Host) bool, error) {
+	var filters []func(host pritunl.Host) bool
+
+	if status, ok := d.GetOk("status"); ok {
+		filters = append(filters, func(host pritunl.Host) bool {
+			return host.Status == status.(string)
+		})
+	}
+
+	if availabilityGroup, ok := d.GetOk("availability_group"); ok {
+		filters = append(filters, func(host pritunl.Host) bool {
+			return host.AvailabilityGroup == availabilityGroup.(string)
+		})
+	}
+
+	if hostnameRegex, ok := d.GetOk("hostname_regex"); ok {
+		re, err := regexp.Compile(hostnameRegex.(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hostname_regex: %s", err)
+		}
+		filters = append(filters, func(host pritunl.Host) bool {
+			return re.MatchString(host.Hostname)
+		})
+	}
+
+	if serverId, ok := d.GetOk("server_id"); ok {
+		serverHosts, err := apiClient.GetHostsByServer(serverId.(string))
+		if err != nil {
+			return nil, err
+		}
+
+		attached := make(map[string]bool)
+		for _, host := range serverHosts {
+			attached[host.ID] = true
+		}
+		filters = append(filters, func(host pritunl.Host) bool {
+			return attached[host.ID]
+		})
+	}
+
+	return filters, nil
+}
+
+func matchHost(host pritunl.Host, filters []func(host pritunl.Host) bool) bool {
+	for _, filter := range filters {
+		if !filter(host) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func flattenHost(host *pritunl.Host) interface{} {
 	result := map[string]interface{}{}
 
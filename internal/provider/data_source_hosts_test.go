@@ -3,6 +3,10 @@ package provider
 import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+	"reflect"
 	"testing"
 )
 
@@ -29,4 +33,88 @@ output "num_hosts" {
   value = length(data.pritunl_hosts.my-server-hosts.hosts)
 }
 `)
-}
\ No newline at end of file
+}
+
+func TestDataSourceHostsFiltersFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	eu1 := fake.AddHost(pritunl.Host{Hostname: "eu-west-1.vpn.internal", AvailabilityGroup: "eu-west", Status: "online"})
+	eu2 := fake.AddHost(pritunl.Host{Hostname: "eu-west-2.vpn.internal", AvailabilityGroup: "eu-west", Status: "offline"})
+	us1 := fake.AddHost(pritunl.Host{Hostname: "us-east-1.vpn.internal", AvailabilityGroup: "us-east", Status: "online"})
+	server := fake.AddServer(pritunl.Server{Name: "tfacc-server1"})
+	fake.AttachHost(server.ID, eu2.ID)
+	fake.AttachHost(server.ID, us1.ID)
+
+	testCases := []struct {
+		name              string
+		config            map[string]interface{}
+		expectedIds       []string
+		expectedOnlineIds []string
+	}{
+		{
+			name:              "without filters",
+			config:            map[string]interface{}{},
+			expectedIds:       []string{eu1.ID, eu2.ID, us1.ID},
+			expectedOnlineIds: []string{eu1.ID, us1.ID},
+		},
+		{
+			name:              "availability group",
+			config:            map[string]interface{}{"availability_group": "eu-west"},
+			expectedIds:       []string{eu1.ID, eu2.ID},
+			expectedOnlineIds: []string{eu1.ID},
+		},
+		{
+			name:              "status and hostname regex",
+			config:            map[string]interface{}{"status": "online", "hostname_regex": `^(eu|us)-[a-z]+-1\.`},
+			expectedIds:       []string{eu1.ID, us1.ID},
+			expectedOnlineIds: []string{eu1.ID, us1.ID},
+		},
+		{
+			name:              "server",
+			config:            map[string]interface{}{"server_id": server.ID, "availability_group": "eu-west"},
+			expectedIds:       []string{eu2.ID},
+			expectedOnlineIds: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := applyTestResourceData(t, dataSourceHosts(), tc.config, dataSourceHostsRead, fake.NewClient())
+
+			if ids := stringList(d.Get("ids")); !reflect.DeepEqual(ids, tc.expectedIds) {
+				t.Fatalf("expected ids %v, got %v", tc.expectedIds, ids)
+			}
+			if onlineIds := stringList(d.Get("online_ids")); !reflect.DeepEqual(onlineIds, tc.expectedOnlineIds) {
+				t.Fatalf("expected online_ids %v, got %v", tc.expectedOnlineIds, onlineIds)
+			}
+			if d.Get("hosts.#").(int) != len(tc.expectedIds) {
+				t.Fatalf("expected %d hosts, got %d", len(tc.expectedIds), d.Get("hosts.#"))
+			}
+		})
+	}
+}
+
+func stringList(value interface{}) []string {
+	result := make([]string, 0)
+	for _, v := range value.([]interface{}) {
+		result = append(result, v.(string))
+	}
+
+	return result
+}
+
+func TestDataSourceHostsValidatesHostnameRegex(t *testing.T) {
+	diags := dataSourceHosts().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"hostname_regex": "eu-(west",
+	}))
+	if !diags.HasError() {
+		t.Fatalf("expected the invalid hostname_regex to be refused when validating")
+	}
+
+	diags = dataSourceHosts().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"hostname_regex": "^eu-west-[0-9]+\\.",
+	}))
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+}
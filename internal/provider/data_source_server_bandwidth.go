@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+func dataSourceServerBandwidth() *schema.Resource {
+	bandwidthSeries := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"timestamp": {
+				Description: "Unix timestamp of the sample",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"bytes": {
+				Description: "Bytes transferred since the previous sample",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Description: "Use this data source to get the bandwidth of a Pritunl server over a period.",
+		ReadContext: dataSourceServerBandwidthRead,
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Description: "ID of the server",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"period": {
+				Description:  fmt.Sprintf("Period of the series, one of %v. The longer the period, the longer the interval between the samples", pritunl.MetricPeriods),
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "1d",
+				ValidateFunc: validation.StringInSlice(pritunl.MetricPeriods, false),
+			},
+			"received": {
+				Description: "Bytes received by the server, the oldest sample first",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        bandwidthSeries,
+			},
+			"sent": {
+				Description: "Bytes sent by the server, the oldest sample first",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        bandwidthSeries,
+			},
+			"received_total": {
+				Description: "Bytes received by the server over the period",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"sent_total": {
+				Description: "Bytes sent by the server over the period",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceServerBandwidthRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	serverId := d.Get("server_id").(string)
+	period := d.Get("period").(string)
+
+	bandwidth, err := apiClient.GetServerBandwidth(serverId, period)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = d.Set("received", flattenBandwidthSeries(bandwidth.Received)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = d.Set("sent", flattenBandwidthSeries(bandwidth.Sent)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("received_total", bandwidth.ReceivedTotal)
+	d.Set("sent_total", bandwidth.SentTotal)
+
+	d.SetId(fmt.Sprintf("%s-%s", serverId, period))
+
+	return nil
+}
+
+func flattenBandwidthSeries(points []pritunl.BandwidthPoint) []interface{} {
+	series := make([]interface{}, 0, len(points))
+	for _, point := range points {
+		series = append(series, map[string]interface{}{
+			"timestamp": int(point.Timestamp),
+			"bytes":     int(point.Bytes),
+		})
+	}
+
+	return series
+}
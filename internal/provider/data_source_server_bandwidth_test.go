@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestDataSourceServerBandwidthFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	server := fake.AddServer(pritunl.Server{Name: "test"})
+	fake.SetServerBandwidth(server.ID, "5m", pritunl.ServerBandwidth{
+		Received:      []pritunl.BandwidthPoint{{Timestamp: 1700000000, Bytes: 1024}, {Timestamp: 1700000300, Bytes: 2048}},
+		ReceivedTotal: 3072,
+		Sent:          []pritunl.BandwidthPoint{{Timestamp: 1700000000, Bytes: 512}, {Timestamp: 1700000300, Bytes: 0}},
+		SentTotal:     512,
+	})
+
+	d := applyTestResourceData(t, dataSourceServerBandwidth(), map[string]interface{}{
+		"server_id": server.ID,
+		"period":    "5m",
+	}, dataSourceServerBandwidthRead, fake.NewClient())
+
+	if d.Get("received.#").(int) != 2 || d.Get("received.1.timestamp").(int) != 1700000300 || d.Get("received.1.bytes").(int) != 2048 {
+		t.Fatalf("expected the received series, got %v", d.Get("received"))
+	}
+	if d.Get("sent.0.bytes").(int) != 512 {
+		t.Fatalf("expected the sent series, got %v", d.Get("sent"))
+	}
+	if d.Get("received_total").(int) != 3072 || d.Get("sent_total").(int) != 512 {
+		t.Fatalf("expected the totals, got %d and %d", d.Get("received_total"), d.Get("sent_total"))
+	}
+
+	d = applyTestResourceData(t, dataSourceServerBandwidth(), map[string]interface{}{
+		"server_id": server.ID,
+	}, dataSourceServerBandwidthRead, fake.NewClient())
+
+	if d.Get("received.#").(int) != 0 || d.Id() != server.ID+"-1d" {
+		t.Fatalf("expected an empty series of the default period, got %v for %s", d.Get("received"), d.Id())
+	}
+
+	if _, err := fake.NewClient().GetServerBandwidth(server.ID, "1h"); err == nil {
+		t.Fatalf("expected an error for an unknown period")
+	}
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+func dataSourceServerClients() *schema.Resource {
+	return &schema.Resource{
+		Description: "Use this data source to get the clients connected to a Pritunl server.",
+		ReadContext: dataSourceServerClientsRead,
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Description: "ID of the server",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"clients": {
+				Description: "Clients connected to the server",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "ID of the connection",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"user_id": {
+							Description: "ID of the user",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"user_name": {
+							Description: "Name of the user",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"organization_id": {
+							Description: "ID of the organization of the user",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"organization_name": {
+							Description: "Name of the organization of the user",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"real_address": {
+							Description: "Address the client connects from",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"virtual_address": {
+							Description: "Address of the client in the network of the server",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"virtual_address6": {
+							Description: "IPv6 address of the client in the network of the server",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"connected_since": {
+							Description: "Time of the connection in the RFC 3339 format",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"device_name": {
+							Description: "Name of the device of the client",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"platform": {
+							Description: "Platform of the device of the client",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceServerClientsRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	serverId := d.Get("server_id").(string)
+	clients, err := apiClient.GetServerClients(serverId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result := make([]interface{}, 0, len(clients))
+	for _, client := range clients {
+		connectedSince := ""
+		if client.ConnectedSince > 0 {
+			connectedSince = time.Unix(client.ConnectedSince, 0).UTC().Format(time.RFC3339)
+		}
+
+		result = append(result, map[string]interface{}{
+			"id":                client.ID,
+			"user_id":           client.UserID,
+			"user_name":         client.UserName,
+			"organization_id":   client.OrganizationID,
+			"organization_name": client.OrganizationName,
+			"real_address":      client.RealAddress,
+			"virtual_address":   client.VirtualAddress,
+			"virtual_address6":  client.VirtualAddress6,
+			"connected_since":   connectedSince,
+			"device_name":       client.DeviceName,
+			"platform":          client.Platform,
+		})
+	}
+
+	if err = d.Set("clients", result); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(serverId)
+
+	return nil
+}
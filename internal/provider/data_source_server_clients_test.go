@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestDataSourceServerClientsFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	server := fake.AddServer(pritunl.Server{Name: "eu"})
+	other := fake.AddServer(pritunl.Server{Name: "us"})
+	organization := fake.AddOrganization("employees")
+	detached := fake.AddOrganization("contractors")
+	fake.AttachOrganization(server.ID, organization.ID)
+
+	alice := fake.AddUser(pritunl.User{
+		Name:         "alice",
+		Organization: organization.ID,
+		Servers: []pritunl.UserServer{
+			{
+				ID:             "client-1",
+				ServerID:       server.ID,
+				Status:         true,
+				RealAddress:    "198.51.100.7",
+				VirtualAddress: "10.1.0.2",
+				ConnectedSince: 1700000000,
+				DeviceName:     "laptop",
+				Platform:       "linux",
+			},
+			{ID: "client-2", ServerID: other.ID, Status: true},
+		},
+	})
+	fake.AddUser(pritunl.User{
+		Name:         "bob",
+		Organization: organization.ID,
+		Servers:      []pritunl.UserServer{{ID: "client-3", ServerID: server.ID, Status: false}},
+	})
+	fake.AddUser(pritunl.User{
+		Name:         "carol",
+		Organization: detached.ID,
+		Servers:      []pritunl.UserServer{{ID: "client-4", ServerID: server.ID, Status: true}},
+	})
+
+	d := applyTestResourceData(t, dataSourceServerClients(), map[string]interface{}{
+		"server_id": server.ID,
+	}, dataSourceServerClientsRead, fake.NewClient())
+
+	if d.Get("clients.#").(int) != 1 {
+		t.Fatalf("expected only the connected client of the server, got %v", d.Get("clients"))
+	}
+
+	expected := map[string]string{
+		"id":                "client-1",
+		"user_id":           alice.ID,
+		"user_name":         "alice",
+		"organization_id":   organization.ID,
+		"organization_name": "employees",
+		"real_address":      "198.51.100.7",
+		"virtual_address":   "10.1.0.2",
+		"connected_since":   "2023-11-14T22:13:20Z",
+		"device_name":       "laptop",
+		"platform":          "linux",
+	}
+	for key, value := range expected {
+		if actual := d.Get("clients.0." + key).(string); actual != value {
+			t.Errorf("expected %s to be %s, got %s", key, value, actual)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+func dataSourceServerOutput() *schema.Resource {
+	return &schema.Resource{
+		Description: "Use this data source to get the recent output of a Pritunl server, e.g. to troubleshoot a server which doesn't start.",
+		ReadContext: dataSourceServerOutputRead,
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Description: "ID of the server",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"max_lines": {
+				Description:  "Return only the last lines of the output, 0 returns all the lines kept by Pritunl",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"output": {
+				Description: "Output lines of the server, the oldest first",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceServerOutputRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	serverId := d.Get("server_id").(string)
+	output, err := apiClient.GetServerOutput(serverId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if maxLines := d.Get("max_lines").(int); maxLines > 0 && len(output) > maxLines {
+		output = output[len(output)-maxLines:]
+	}
+
+	d.Set("output", output)
+	d.SetId(serverId)
+
+	return nil
+}
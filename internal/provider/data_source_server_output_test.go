@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestDataSourceServerOutputFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	server := fake.AddServer(pritunl.Server{Name: "test"})
+	fake.AddServerOutput(server.ID, "first", "second", "third")
+
+	d := applyTestResourceData(t, dataSourceServerOutput(), map[string]interface{}{
+		"server_id": server.ID,
+		"max_lines": 2,
+	}, dataSourceServerOutputRead, fake.NewClient())
+
+	if output := d.Get("output").([]interface{}); !reflect.DeepEqual(output, []interface{}{"second", "third"}) {
+		t.Fatalf("expected the last lines, got %v", output)
+	}
+	if d.Id() != server.ID {
+		t.Fatalf("expected the server ID, got %s", d.Id())
+	}
+}
+
+func TestServerDiagnosticsAttachOutput(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	apiClient := fake.NewClient()
+	server := fake.AddServer(pritunl.Server{Name: "test"})
+	for i := 0; i < serverOutputLines+5; i++ {
+		fake.AddServerOutput(server.ID, fmt.Sprintf("line %d", i))
+	}
+	fake.FailRequests("PUT /server/{id}/operation/{operation}", 400)
+
+	diags := serverDiagnostics(apiClient, startServer(apiClient, server.ID))
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "Error on starting server "+server.ID) {
+		t.Fatalf("expected the start to fail, got %v", diags)
+	}
+
+	lines := strings.Split(diags[0].Detail, "\n")
+	if len(lines) != serverOutputLines+1 || lines[1] != "line 5" || lines[serverOutputLines] != fmt.Sprintf("line %d", serverOutputLines+4) {
+		t.Fatalf("expected the last %d output lines, got %q", serverOutputLines, diags[0].Detail)
+	}
+
+	diags = serverDiagnostics(apiClient, fmt.Errorf("unrelated"))
+	if diags[0].Detail != "" {
+		t.Fatalf("expected no output for other errors, got %q", diags[0].Detail)
+	}
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+func dataSourceUserAudit() *schema.Resource {
+	return &schema.Resource{
+		Description: "Use this data source to get the audit events of a Pritunl user. " +
+			"The events are only recorded for the users with the `audit` attribute enabled.",
+		ReadContext: dataSourceUserAuditRead,
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Description: "ID of the user",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"organization_id": {
+				Description: "ID of the organization of the user",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"since": {
+				Description:  "Only return the events recorded at or after this time, in the RFC 3339 format",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"until": {
+				Description:  "Only return the events recorded at or before this time, in the RFC 3339 format",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"types": {
+				Description: "Only return the events of these types, e.g. `user_profile` or `user_created`",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"events": {
+				Description: "Audit events of the user, the newest first",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Description: "ID of the event",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"timestamp": {
+							Description: "Time of the event in the RFC 3339 format",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"type": {
+							Description: "Type of the event",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"remote_address": {
+							Description: "Address the event came from",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"message": {
+							Description: "Description of the event",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceUserAuditRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	userId := d.Get("user_id").(string)
+	events, err := apiClient.GetUserAuditEvents(userId, d.Get("organization_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the values are validated by the schema
+	var since, until time.Time
+	if v, ok := d.GetOk("since"); ok {
+		since, _ = time.Parse(time.RFC3339, v.(string))
+	}
+	if v, ok := d.GetOk("until"); ok {
+		until, _ = time.Parse(time.RFC3339, v.(string))
+	}
+
+	types := make([]string, 0)
+	for _, v := range d.Get("types").(*schema.Set).List() {
+		types = append(types, v.(string))
+	}
+
+	slices.SortStableFunc(events, func(a, b pritunl.AuditEvent) int {
+		return cmp.Compare(b.Timestamp, a.Timestamp)
+	})
+
+	result := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		timestamp := time.Unix(event.Timestamp, 0).UTC()
+		if !since.IsZero() && timestamp.Before(since) {
+			continue
+		}
+		if !until.IsZero() && timestamp.After(until) {
+			continue
+		}
+		if len(types) > 0 && !slices.Contains(types, event.Type) {
+			continue
+		}
+
+		result = append(result, map[string]interface{}{
+			"id":             event.ID,
+			"timestamp":      timestamp.Format(time.RFC3339),
+			"type":           event.Type,
+			"remote_address": event.RemoteAddr,
+			"message":        event.Message,
+		})
+	}
+
+	if err = d.Set("events", result); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(userId)
+
+	return nil
+}
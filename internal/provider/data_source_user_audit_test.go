@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestDataSourceUserAuditFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	organization := fake.AddOrganization("employees")
+	alice := fake.AddUser(pritunl.User{Name: "alice", Organization: organization.ID, Audit: true})
+	fake.AddUserAuditEvents(alice.ID,
+		pritunl.AuditEvent{ID: "created", Timestamp: 1700000000, Type: "user_created", RemoteAddr: "198.51.100.7", Message: "User created from web console"},
+		pritunl.AuditEvent{ID: "profile", Timestamp: 1700003600, Type: "user_profile", RemoteAddr: "198.51.100.8", Message: "User profile downloaded from web console"},
+		pritunl.AuditEvent{ID: "connected", Timestamp: 1700007200, Type: "user_connection", RemoteAddr: "203.0.113.9", Message: "User connected to \"eu\""},
+	)
+
+	read := func(t *testing.T, raw map[string]interface{}) *schema.ResourceData {
+		raw["user_id"] = alice.ID
+		raw["organization_id"] = organization.ID
+
+		d := applyTestResourceData(t, dataSourceUserAudit(), raw, dataSourceUserAuditRead, fake.NewClient())
+
+		return d
+	}
+
+	t.Run("returns all the events, the newest first", func(t *testing.T) {
+		d := read(t, map[string]interface{}{})
+
+		if d.Id() != alice.ID || d.Get("events.#").(int) != 3 {
+			t.Fatalf("expected the three events of the user, got %v", d.Get("events"))
+		}
+
+		expected := map[string]string{
+			"id":             "connected",
+			"timestamp":      "2023-11-15T00:13:20Z",
+			"type":           "user_connection",
+			"remote_address": "203.0.113.9",
+			"message":        "User connected to \"eu\"",
+		}
+		for key, value := range expected {
+			if actual := d.Get("events.0." + key).(string); actual != value {
+				t.Errorf("expected %s to be %s, got %s", key, value, actual)
+			}
+		}
+	})
+
+	t.Run("filters the events by time range", func(t *testing.T) {
+		d := read(t, map[string]interface{}{
+			"since": "2023-11-14T22:13:20Z",
+			"until": "2023-11-14T23:30:00Z",
+		})
+
+		if d.Get("events.#").(int) != 2 || d.Get("events.0.id") != "profile" || d.Get("events.1.id") != "created" {
+			t.Fatalf("expected the events within the range, got %v", d.Get("events"))
+		}
+	})
+
+	t.Run("filters the events by type", func(t *testing.T) {
+		d := read(t, map[string]interface{}{
+			"types": []interface{}{"user_profile", "user_connection"},
+			"until": "2023-11-14T23:30:00Z",
+		})
+
+		if d.Get("events.#").(int) != 1 || d.Get("events.0.id") != "profile" {
+			t.Fatalf("expected only the profile event, got %v", d.Get("events"))
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"sort"
+	"sync"
+)
+
+// mutexKV is a registry of mutexes identified by keys, e.g. IDs of the servers
+type mutexKV struct {
+	lock  sync.Mutex
+	store map[string]*sync.Mutex
+}
+
+func newMutexKV() *mutexKV {
+	return &mutexKV{
+		store: make(map[string]*sync.Mutex),
+	}
+}
+
+// Lock locks the mutex of the key, creating it when needed
+func (m *mutexKV) Lock(key string) {
+	m.get(key).Lock()
+}
+
+// Unlock unlocks the mutex of the key
+func (m *mutexKV) Unlock(key string) {
+	m.get(key).Unlock()
+}
+
+// LockAll locks the mutexes of all the keys and returns the function unlocking them.
+// Keys are locked in the sorted order, so concurrent calls with overlapping keys can't deadlock.
+func (m *mutexKV) LockAll(keys ...string) func() {
+	sorted := make([]string, 0, len(keys))
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, found := seen[key]; found {
+			continue
+		}
+		seen[key] = struct{}{}
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		m.Lock(key)
+	}
+
+	return func() {
+		for i := len(sorted) - 1; i >= 0; i-- {
+			m.Unlock(sorted[i])
+		}
+	}
+}
+
+func (m *mutexKV) get(key string) *sync.Mutex {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	mutex, ok := m.store[key]
+	if !ok {
+		mutex = &sync.Mutex{}
+		m.store[key] = mutex
+	}
+
+	return mutex
+}
+
+// defaultLocks is used when the provider meta has no lock registry, e.g. when resources are called with a bare client
+var defaultLocks = newMutexKV()
+
+// lockServers locks the servers while they are stopped, reconfigured or started
+// and returns the function unlocking them
+func lockServers(meta interface{}, serverIds ...string) func() {
+	keys := make([]string, 0, len(serverIds))
+	for _, id := range serverIds {
+		keys = append(keys, "server/"+id)
+	}
+
+	return providerLocks(meta).LockAll(keys...)
+}
+
+// lockNetworkPools locks the network pools while a network is allocated from one of them and saved to a server
+// and returns the function unlocking them. The pools share the lock, since different pools can overlap.
+func lockNetworkPools(meta interface{}) func() {
+	return providerLocks(meta).LockAll("network_pool")
+}
+
+// lockSuperUsers locks the super users while one of them is demoted, disabled or deleted,
+// so two of them can't both rely on the other one being left, and returns the function unlocking them
+func lockSuperUsers(meta interface{}) func() {
+	return providerLocks(meta).LockAll("administrator/super_user")
+}
+
+// providerLocks returns the lock registry of the provider meta
+func providerLocks(meta interface{}) *mutexKV {
+	if m, ok := meta.(*providerMeta); ok && m.locks != nil {
+		return m.locks
+	}
+
+	return defaultLocks
+}
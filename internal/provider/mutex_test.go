@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMutexKVSerializesTheSameKey(t *testing.T) {
+	locks := newMutexKV()
+
+	var running, maxRunning atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			locks.Lock("server/1")
+			defer locks.Unlock("server/1")
+
+			if current := running.Add(1); current > maxRunning.Load() {
+				maxRunning.Store(current)
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	if maxRunning.Load() != 1 {
+		t.Fatalf("expected the operations to be serialized, got %d running at the same time", maxRunning.Load())
+	}
+}
+
+func TestMutexKVLockAllDoesNotDeadlock(t *testing.T) {
+	locks := newMutexKV()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				unlock := locks.LockAll("server/a", "server/b", "server/a")
+				time.Sleep(time.Millisecond)
+				unlock()
+			}()
+			go func() {
+				defer wg.Done()
+				unlock := locks.LockAll("server/b", "server/a")
+				time.Sleep(time.Millisecond)
+				unlock()
+			}()
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("locking servers in different orders deadlocked")
+	}
+}
+
+func TestLockServersUsesProviderLocks(t *testing.T) {
+	meta := &providerMeta{locks: newMutexKV()}
+
+	unlock := lockServers(meta, "1", "2")
+
+	locked := make(chan struct{})
+	go func() {
+		defer close(locked)
+		lockServers(meta, "2")()
+	}()
+
+	select {
+	case <-locked:
+		t.Fatalf("expected the server to stay locked")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	unlock()
+	<-locked
+}
@@ -0,0 +1,219 @@
+package provider
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+// defaultAllowedClientNetworks are used when allowed_client_networks isn't set in the provider configuration
+var defaultAllowedClientNetworks = []string{
+	// RFC1918
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	// IPv6 ULA
+	"fc00::/7",
+}
+
+// validateCIDRNetwork checks that the value is a network address with a subnet
+func validateCIDRNetwork(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	if _, _, err := net.ParseCIDR(v); err != nil {
+		return nil, []error{err}
+	}
+
+	return nil, nil
+}
+
+// validateRouteNetwork checks that the value is a network address with a subnet
+// and warns when the address has host bits set, since Pritunl stores the canonical network only
+func validateRouteNetwork(i interface{}, k string) ([]string, []error) {
+	warnings, errors := validateCIDRNetwork(i, k)
+	if len(errors) > 0 {
+		return warnings, errors
+	}
+
+	network := i.(string)
+	if pritunl.NetworkHasHostBits(network) {
+		warnings = append(warnings, fmt.Sprintf("%s: %s has host bits set, it will be stored as %s", k, network, pritunl.NormalizeNetwork(network)))
+	}
+
+	return warnings, errors
+}
+
+// suppressEquivalentNetworkDiff suppresses the diff between different notations of the same network, e.g. 10.0.0.1/24 and 10.0.0.0/24
+func suppressEquivalentNetworkDiff(_, old, new string, _ *schema.ResourceData) bool {
+	return pritunl.NormalizeNetwork(old) == pritunl.NormalizeNetwork(new)
+}
+
+// networkValidator checks that networks belong to one of the allowed network ranges
+type networkValidator struct {
+	allowedNetworks []netip.Prefix
+}
+
+func newNetworkValidator(allowedNetworks []string) (*networkValidator, error) {
+	validator := &networkValidator{}
+
+	for _, v := range allowedNetworks {
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed network %s: %s", v, err)
+		}
+		validator.allowedNetworks = append(validator.allowedNetworks, prefix.Masked())
+	}
+
+	return validator, nil
+}
+
+// validate checks the network, IPv6 networks are accepted only when allowIPv6 is set
+func (v *networkValidator) validate(network string, allowIPv6 bool) error {
+	_, ipNet, err := net.ParseCIDR(network)
+	if err != nil {
+		return err
+	}
+
+	prefix, err := netip.ParsePrefix(ipNet.String())
+	if err != nil {
+		return err
+	}
+
+	if prefix.Addr().Is6() && !allowIPv6 {
+		return fmt.Errorf("provided subnet %s is an IPv6 network, but IPv6 is not enabled", prefix)
+	}
+
+	expectedNetworks := make([]string, 0)
+	for _, allowedNetwork := range v.allowedNetworks {
+		if allowedNetwork.Addr().Is4() != prefix.Addr().Is4() {
+			continue
+		}
+
+		if allowedNetwork.Bits() <= prefix.Bits() && allowedNetwork.Contains(prefix.Addr()) {
+			return nil
+		}
+
+		expectedNetworks = append(expectedNetworks, allowedNetwork.String())
+	}
+
+	return fmt.Errorf("provided subnet %s does not belong to expected subnets %s", prefix, strings.Join(expectedNetworks, ", "))
+}
+
+// usedServerNetworks collects the client networks of all the servers except the one with excludeId
+func usedServerNetworks(servers []pritunl.Server, excludeId string) []string {
+	networks := make([]string, 0)
+
+	for _, server := range servers {
+		if server.ID == excludeId {
+			continue
+		}
+		if server.Network != "" {
+			networks = append(networks, server.Network)
+		}
+		if server.NetworkWG != "" {
+			networks = append(networks, server.NetworkWG)
+		}
+	}
+
+	return networks
+}
+
+// parseNetworkPool parses the pool and checks that networks with the prefix length can be allocated from it
+func parseNetworkPool(pool string, prefixLength int) (netip.Prefix, error) {
+	poolPrefix, err := netip.ParsePrefix(pool)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid network pool %s: %s", pool, err)
+	}
+	poolPrefix = poolPrefix.Masked()
+
+	if !poolPrefix.Addr().Is4() {
+		return netip.Prefix{}, fmt.Errorf("network pool %s must be an IPv4 network", pool)
+	}
+
+	if prefixLength < poolPrefix.Bits() || prefixLength > 32 {
+		return netip.Prefix{}, fmt.Errorf("prefix length /%d must be between /%d and /32 for the network pool %s", prefixLength, poolPrefix.Bits(), poolPrefix)
+	}
+
+	return poolPrefix, nil
+}
+
+// findAvailableNetwork returns the first subnet with the given prefix length inside the pool
+// which doesn't overlap any of the used networks
+func findAvailableNetwork(pool string, prefixLength int, usedNetworks []string) (string, error) {
+	poolPrefix, err := parseNetworkPool(pool, prefixLength)
+	if err != nil {
+		return "", err
+	}
+
+	used := make([]netip.Prefix, 0, len(usedNetworks))
+	for _, v := range usedNetworks {
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil || !prefix.Addr().Is4() {
+			// networks which cannot be parsed can't overlap the pool either
+			continue
+		}
+		used = append(used, prefix.Masked())
+	}
+
+	size := uint64(1) << (32 - prefixLength)
+	poolStart := uint64(ipv4ToUint32(poolPrefix.Addr()))
+	poolEnd := poolStart + (uint64(1) << (32 - poolPrefix.Bits()))
+
+	for start := poolStart; start+size <= poolEnd; {
+		candidate := netip.PrefixFrom(uint32ToIPv4(uint32(start)), prefixLength)
+
+		overlapEnd := uint64(0)
+		for _, prefix := range used {
+			if !candidate.Overlaps(prefix) {
+				continue
+			}
+
+			prefixEnd := uint64(ipv4ToUint32(prefix.Addr())) + (uint64(1) << (32 - prefix.Bits()))
+			if prefixEnd > overlapEnd {
+				overlapEnd = prefixEnd
+			}
+		}
+
+		if overlapEnd == 0 {
+			return candidate.String(), nil
+		}
+
+		// skip all the candidates covered by the overlapping network, keeping the alignment
+		start = (overlapEnd + size - 1) / size * size
+	}
+
+	return "", fmt.Errorf("no available /%d network left in the network pool %s", prefixLength, poolPrefix)
+}
+
+// networkFitsPool checks that the network has the given prefix length and belongs to the pool
+func networkFitsPool(network, pool string, prefixLength int) bool {
+	networkPrefix, err := netip.ParsePrefix(network)
+	if err != nil {
+		return false
+	}
+
+	poolPrefix, err := netip.ParsePrefix(pool)
+	if err != nil {
+		return false
+	}
+
+	return networkPrefix.Bits() == prefixLength &&
+		networkPrefix.Bits() >= poolPrefix.Bits() &&
+		poolPrefix.Masked().Contains(networkPrefix.Addr())
+}
+
+func ipv4ToUint32(addr netip.Addr) uint32 {
+	b := addr.As4()
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+func uint32ToIPv4(v uint32) netip.Addr {
+	return netip.AddrFrom4([4]byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)})
+}
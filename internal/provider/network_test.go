@@ -0,0 +1,285 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestFindAvailableNetwork(t *testing.T) {
+	testCases := []struct {
+		name         string
+		pool         string
+		prefixLength int
+		used         []string
+		expected     string
+		expectError  bool
+	}{
+		{
+			name:         "returns the first network of an empty pool",
+			pool:         "10.100.0.0/16",
+			prefixLength: 24,
+			expected:     "10.100.0.0/24",
+		},
+		{
+			name:         "skips used networks",
+			pool:         "10.100.0.0/16",
+			prefixLength: 24,
+			used:         []string{"10.100.0.0/24", "10.100.1.0/24", "192.168.1.0/24"},
+			expected:     "10.100.2.0/24",
+		},
+		{
+			name:         "skips networks overlapped by a bigger used network",
+			pool:         "10.100.0.0/16",
+			prefixLength: 24,
+			used:         []string{"10.100.0.0/22"},
+			expected:     "10.100.4.0/24",
+		},
+		{
+			name:         "skips networks overlapped by a smaller used network",
+			pool:         "10.100.0.0/16",
+			prefixLength: 24,
+			used:         []string{"10.100.0.128/25"},
+			expected:     "10.100.1.0/24",
+		},
+		{
+			name:         "fills gaps between used networks",
+			pool:         "10.100.0.0/16",
+			prefixLength: 24,
+			used:         []string{"10.100.0.0/24", "10.100.2.0/24"},
+			expected:     "10.100.1.0/24",
+		},
+		{
+			name:         "ignores host bits of the pool",
+			pool:         "10.100.7.1/16",
+			prefixLength: 24,
+			expected:     "10.100.0.0/24",
+		},
+		{
+			name:         "fails when the pool is exhausted",
+			pool:         "10.100.0.0/23",
+			prefixLength: 24,
+			used:         []string{"10.100.0.0/24", "10.100.1.0/24"},
+			expectError:  true,
+		},
+		{
+			name:         "fails when the prefix is bigger than the pool",
+			pool:         "10.100.0.0/24",
+			prefixLength: 16,
+			expectError:  true,
+		},
+		{
+			name:         "fails on an IPv6 pool",
+			pool:         "fd00::/48",
+			prefixLength: 64,
+			expectError:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			network, err := findAvailableNetwork(testCase.pool, testCase.prefixLength, testCase.used)
+			if testCase.expectError {
+				if err == nil {
+					t.Fatalf("expected an error, got network %s", network)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if network != testCase.expected {
+				t.Fatalf("expected network %s, got %s", testCase.expected, network)
+			}
+		})
+	}
+}
+
+func TestNetworkFitsPool(t *testing.T) {
+	if !networkFitsPool("10.100.3.0/24", "10.100.0.0/16", 24) {
+		t.Fatalf("expected 10.100.3.0/24 to fit the pool 10.100.0.0/16")
+	}
+	if networkFitsPool("10.101.3.0/24", "10.100.0.0/16", 24) {
+		t.Fatalf("expected 10.101.3.0/24 not to fit the pool 10.100.0.0/16")
+	}
+	if networkFitsPool("10.100.3.0/24", "10.100.0.0/16", 25) {
+		t.Fatalf("expected 10.100.3.0/24 not to fit the prefix length /25")
+	}
+}
+
+func TestNetworkValidator(t *testing.T) {
+	defaultValidator, err := newNetworkValidator(defaultAllowedClientNetworks)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cgnatValidator, err := newNetworkValidator([]string{"100.64.0.0/10"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		name        string
+		validator   *networkValidator
+		network     string
+		allowIPv6   bool
+		expectError string
+	}{
+		{name: "accepts RFC1918 network", validator: defaultValidator, network: "172.16.68.0/24"},
+		{name: "accepts the whole RFC1918 range", validator: defaultValidator, network: "10.0.0.0/8"},
+		{
+			name:        "rejects a network outside of RFC1918",
+			validator:   defaultValidator,
+			network:     "172.14.68.0/24",
+			expectError: "provided subnet 172.14.68.0/24 does not belong to expected subnets 10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16",
+		},
+		{
+			name:        "rejects a network wider than the allowed range",
+			validator:   defaultValidator,
+			network:     "10.0.0.0/7",
+			expectError: "provided subnet 10.0.0.0/7 does not belong to expected subnets",
+		},
+		{name: "accepts CGNAT network when allowed", validator: cgnatValidator, network: "100.100.0.0/16"},
+		{
+			name:        "rejects RFC1918 network when only CGNAT is allowed",
+			validator:   cgnatValidator,
+			network:     "10.10.0.0/16",
+			expectError: "provided subnet 10.10.0.0/16 does not belong to expected subnets 100.64.0.0/10",
+		},
+		{name: "accepts IPv6 ULA network when IPv6 is enabled", validator: defaultValidator, network: "fd00:1234::/64", allowIPv6: true},
+		{
+			name:        "rejects IPv6 ULA network when IPv6 is disabled",
+			validator:   defaultValidator,
+			network:     "fd00:1234::/64",
+			expectError: "is an IPv6 network, but IPv6 is not enabled",
+		},
+		{
+			name:        "rejects a global IPv6 network",
+			validator:   defaultValidator,
+			network:     "2001:db8::/64",
+			allowIPv6:   true,
+			expectError: "provided subnet 2001:db8::/64 does not belong to expected subnets fc00::/7",
+		},
+		{
+			name:        "rejects an invalid network",
+			validator:   defaultValidator,
+			network:     "10.100.0.2",
+			expectError: "invalid CIDR address: 10.100.0.2",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.validator.validate(testCase.network, testCase.allowIPv6)
+			if testCase.expectError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), testCase.expectError) {
+				t.Fatalf("expected error containing %q, got %v", testCase.expectError, err)
+			}
+		})
+	}
+}
+
+func TestValidateRouteNetwork(t *testing.T) {
+	warnings, errors := validateRouteNetwork("10.0.0.0/24", "network")
+	if len(warnings) != 0 || len(errors) != 0 {
+		t.Fatalf("expected no warnings and errors, got %v and %v", warnings, errors)
+	}
+
+	warnings, errors = validateRouteNetwork("10.0.0.1/24", "network")
+	if len(errors) != 0 {
+		t.Fatalf("unexpected errors: %v", errors)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "it will be stored as 10.0.0.0/24") {
+		t.Fatalf("expected a warning about host bits, got %v", warnings)
+	}
+
+	warnings, errors = validateRouteNetwork("fd00::1/64", "network")
+	if len(errors) != 0 || len(warnings) != 1 {
+		t.Fatalf("expected a warning about host bits of an IPv6 network, got %v and %v", warnings, errors)
+	}
+
+	_, errors = validateRouteNetwork("10.100.0.2", "network")
+	if len(errors) != 1 {
+		t.Fatalf("expected an error for a network without a subnet")
+	}
+}
+
+func TestSuppressEquivalentNetworkDiff(t *testing.T) {
+	if !suppressEquivalentNetworkDiff("network", "10.0.0.0/24", "10.0.0.1/24", nil) {
+		t.Errorf("expected diff between 10.0.0.0/24 and 10.0.0.1/24 to be suppressed")
+	}
+	if !suppressEquivalentNetworkDiff("network", "fd00::/64", "FD00::1/64", nil) {
+		t.Errorf("expected diff between fd00::/64 and FD00::1/64 to be suppressed")
+	}
+	if suppressEquivalentNetworkDiff("network", "10.0.0.0/24", "10.0.0.0/25", nil) {
+		t.Errorf("expected diff between 10.0.0.0/24 and 10.0.0.0/25 not to be suppressed")
+	}
+}
+
+func TestMatchRoutesWithSchemaNormalizesNetworks(t *testing.T) {
+	routes := []pritunl.Route{
+		{Network: "10.2.0.0/24"},
+		{Network: "10.1.0.0/24"},
+	}
+	declaredRoutes := []interface{}{
+		map[string]interface{}{"network": "10.1.0.1/24", "nat": false, "net_gateway": false},
+		map[string]interface{}{"network": "10.2.0.0/24", "nat": false, "net_gateway": false},
+	}
+
+	result := matchRoutesWithSchema(routes, declaredRoutes)
+	if len(result) != 2 || result[0].Network != "10.1.0.0/24" || result[1].Network != "10.2.0.0/24" {
+		t.Fatalf("unexpected routes order: %+v", result)
+	}
+}
+
+func TestResourceServerOverlappingNetworkPoolsFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+	fake.SetLatency(10 * time.Millisecond)
+
+	apiClient := pritunl.NewCachingClient(fake.NewClient())
+
+	pools := []string{"10.100.0.0/16", "10.100.0.0/20"}
+	resources := make([]*schema.ResourceData, len(pools))
+	for i, pool := range pools {
+		resources[i] = schema.TestResourceDataRaw(t, resourceServer().Schema, map[string]interface{}{
+			"name":         fmt.Sprintf("pool-%d", i),
+			"network_pool": pool,
+		})
+	}
+
+	diags := make([]diag.Diagnostics, len(resources))
+	var wg sync.WaitGroup
+	for i, d := range resources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			diags[i] = resourceCreateServer(context.Background(), d, apiClient)
+		}()
+	}
+	wg.Wait()
+
+	networks := make(map[string]bool)
+	for i, d := range resources {
+		if diags[i].HasError() {
+			t.Fatalf("unexpected error: %v", diags[i])
+		}
+		networks[d.Get("network").(string)] = true
+	}
+
+	if len(networks) != 2 {
+		t.Fatalf("expected the servers of the overlapping pools to get different networks, got %v", networks)
+	}
+}
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// maxParallelReads limits the number of concurrent requests made by a single composite read
+const maxParallelReads = 4
+
+// fetchParallel runs the fetch functions concurrently with at most limit of them running at a time.
+// All the fetch functions are run even if some of them fail, their errors are joined in the order of the functions.
+// Functions which haven't been started yet are skipped once the context is done.
+func fetchParallel(ctx context.Context, limit int, fetches ...func() error) error {
+	if limit < 1 {
+		limit = 1
+	}
+
+	errs := make([]error, len(fetches))
+	semaphore := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	for i, fetch := range fetches {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, fetch func() error) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			errs[i] = fetch()
+		}(i, fetch)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
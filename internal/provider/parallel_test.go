@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestFetchParallel(t *testing.T) {
+	t.Run("runs all the functions with bounded parallelism", func(t *testing.T) {
+		var running, maxRunning, calls atomic.Int32
+
+		fetch := func() error {
+			current := running.Add(1)
+			defer running.Add(-1)
+
+			for {
+				max := maxRunning.Load()
+				if current <= max || maxRunning.CompareAndSwap(max, current) {
+					break
+				}
+			}
+
+			calls.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}
+
+		err := fetchParallel(context.Background(), 2, fetch, fetch, fetch, fetch, fetch)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if calls.Load() != 5 {
+			t.Fatalf("expected 5 calls, got %d", calls.Load())
+		}
+		if maxRunning.Load() != 2 {
+			t.Fatalf("expected at most 2 concurrent calls, got %d", maxRunning.Load())
+		}
+	})
+
+	t.Run("joins the errors of all the functions", func(t *testing.T) {
+		first := errors.New("first")
+		second := errors.New("second")
+
+		err := fetchParallel(context.Background(), 4,
+			func() error { return first },
+			func() error { return nil },
+			func() error { return second },
+		)
+		if !errors.Is(err, first) || !errors.Is(err, second) {
+			t.Fatalf("expected both errors, got %v", err)
+		}
+		if err.Error() != "first\nsecond" {
+			t.Fatalf("expected errors in the order of the functions, got %q", err)
+		}
+	})
+
+	t.Run("skips the functions after the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var calls atomic.Int32
+		err := fetchParallel(ctx, 1, func() error {
+			calls.Add(1)
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if calls.Load() != 0 {
+			t.Fatalf("expected no calls, got %d", calls.Load())
+		}
+	})
+}
+
+func TestResourceReadServerFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	server := seedFakeServer(fake)
+
+	d := resourceServer().Data(nil)
+	d.SetId(server.ID)
+
+	if diags := resourceReadServer(context.Background(), d, fake.NewClient()); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if d.Get("name").(string) != server.Name {
+		t.Fatalf("expected name %s, got %s", server.Name, d.Get("name"))
+	}
+	if d.Get("organization_ids.#").(int) != 1 || d.Get("host_ids.#").(int) != 1 || d.Get("route.#").(int) != 1 {
+		t.Fatalf("expected an organization, a host and a route, got %v, %v and %v", d.Get("organization_ids"), d.Get("host_ids"), d.Get("route"))
+	}
+
+	fake.FailRequests("GET /server/{id}/route", 500)
+	fake.FailRequests("GET /server/{id}/host", 500)
+
+	diags := resourceReadServer(context.Background(), d, fake.NewClient())
+	if !diags.HasError() {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(diags[0].Summary, "GetRoutesByServer") || !strings.Contains(diags[0].Summary, "GetHostsByServer") {
+		t.Fatalf("expected errors of both failed requests, got %q", diags[0].Summary)
+	}
+}
+
+// BenchmarkResourceReadServer compares sequential requests of the server objects
+// with the parallel ones made by resourceReadServer against a server with a network latency
+func BenchmarkResourceReadServer(b *testing.B) {
+	fake := pritunltest.NewTestServer(b)
+
+	server := seedFakeServer(fake)
+	fake.SetLatency(20 * time.Millisecond)
+	apiClient := fake.NewClient()
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := apiClient.GetServer(server.ID); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := apiClient.GetOrganizationsByServer(server.ID); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := apiClient.GetRoutesByServer(server.ID); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := apiClient.GetHostsByServer(server.ID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			d := resourceServer().Data(nil)
+			d.SetId(server.ID)
+
+			if diags := resourceReadServer(context.Background(), d, apiClient); diags.HasError() {
+				b.Fatal(diags)
+			}
+		}
+	})
+}
+
+func seedFakeServer(fake *pritunltest.Server) pritunl.Server {
+	organization := fake.AddOrganization("tfacc-org1")
+	host := fake.AddHost(pritunl.Host{Name: "host1", Hostname: "host1", Status: "online"})
+	server := fake.AddServer(pritunl.Server{Name: "tfacc-server1", Network: "172.16.68.0/24", Port: 15500})
+
+	fake.AttachOrganization(server.ID, organization.ID)
+	fake.AttachHost(server.ID, host.ID)
+	fake.AddRoute(server.ID, pritunl.Route{Network: "10.5.0.0/24", Comment: "tfacc-route", Nat: true})
+
+	return server
+}
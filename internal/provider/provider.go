@@ -2,9 +2,13 @@ package provider
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
 )
 
@@ -13,57 +17,273 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"url": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_URL", ""),
+				Description: "URL of the Pritunl API, required unless it's set in the profile or returned by the `credential_process`",
+			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_PROFILE", ""),
+				Description: "Profile of the `shared_credentials_file` the `url`, `token`, `secret` and `credential_process` are read from. The `default` profile is used when it's not set and the profile exists. Can be set with the `PRITUNL_PROFILE` environment variable",
+			},
+			"shared_credentials_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_SHARED_CREDENTIALS_FILE", defaultCredentialsFile()),
+				Description: "Path of the INI formatted file with the credential profiles. Defaults to `~/.config/pritunl/credentials`. Can be set with the `PRITUNL_SHARED_CREDENTIALS_FILE` environment variable",
+			},
+			"credential_process": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_CREDENTIAL_PROCESS", ""),
+				Description: "Command returning the `url`, `token` and `secret` as a JSON object, e.g. `{\"token\": \"...\", \"secret\": \"...\"}`. It's run with `sh -c`, or `cmd /C` on Windows, only when some of the values are not set with the provider attributes or the environment variables. Can be set with the `PRITUNL_CREDENTIAL_PROCESS` environment variable or in the profile",
+			},
+			"auth_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("PRITUNL_AUTH_MODE", "token"),
+				ValidateFunc: validation.StringInSlice([]string{"token", "session"}, false),
+				Description:  "Authenticate with the API `token` and `secret`, or with the `session` of an administrator logged in with the `username` and `password`, for the Pritunl servers with the API keys disabled. The session is logged in again when it expires. Defaults to `token`. Can be set with the `PRITUNL_AUTH_MODE` environment variable",
 			},
 			"token": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_TOKEN", ""),
+				Description: "API token, required with the `token` auth_mode",
 			},
 			"secret": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_SECRET", ""),
+				Description: "API secret, required with the `token` auth_mode",
+			},
+			"username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_USERNAME", ""),
+				Description: "Username of the administrator, required with the `session` auth_mode. Can be set with the `PRITUNL_USERNAME` environment variable",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_PASSWORD", ""),
+				Description: "Password of the administrator, required with the `session` auth_mode. Can be set with the `PRITUNL_PASSWORD` environment variable",
 			},
 			"insecure": {
 				Type:        schema.TypeBool,
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_INSECURE", false),
 			},
+			"ca_cert_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("PRITUNL_CA_CERT_PEM", ""),
+				ConflictsWith: []string{"ca_cert_file"},
+				Description:   "PEM encoded certificates of the CA which issued the certificate of the Pritunl API, trusted in addition to the system CAs. Can be set with the `PRITUNL_CA_CERT_PEM` environment variable",
+			},
+			"ca_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("PRITUNL_CA_CERT_FILE", ""),
+				ConflictsWith: []string{"ca_cert_pem"},
+				Description:   "Path to a file with PEM encoded certificates of the CA which issued the certificate of the Pritunl API, can't be combined with `ca_cert_pem`, including through the environment variables. Can be set with the `PRITUNL_CA_CERT_FILE` environment variable",
+			},
+			"client_cert_pem": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("PRITUNL_CLIENT_CERT_PEM", ""),
+				RequiredWith: []string{"client_key_pem"},
+				Description:  "PEM encoded client certificate for mutual TLS, requires `client_key_pem`. Can be set with the `PRITUNL_CLIENT_CERT_PEM` environment variable",
+			},
+			"client_key_pem": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				DefaultFunc:  schema.EnvDefaultFunc("PRITUNL_CLIENT_KEY_PEM", ""),
+				RequiredWith: []string{"client_cert_pem"},
+				Description:  "PEM encoded private key of the client certificate for mutual TLS. Can be set with the `PRITUNL_CLIENT_KEY_PEM` environment variable",
+			},
+			"tls_server_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_TLS_SERVER_NAME", ""),
+				Description: "Name the certificate of the Pritunl API is verified against, when it differs from the host of the url. Can be set with the `PRITUNL_TLS_SERVER_NAME` environment variable",
+			},
 			"connection_check": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_CONNECTION_CHECK", true),
 			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("PRITUNL_MAX_CONCURRENT_REQUESTS", 10),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of requests sent to the Pritunl API at the same time, 0 means no limit. Requests rejected with 429 or 503 status codes are retried according to the Retry-After header",
+			},
+			"proxy_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("PRITUNL_PROXY_URL", ""),
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "socks5"}),
+				Description:  "URL of the proxy the Pritunl API is reached through, with the `http`, `https` or `socks5` scheme. Unlike the `HTTPS_PROXY` environment variable it only applies to this provider. The proxy environment variables are used when it's not set. Can be set with the `PRITUNL_PROXY_URL` environment variable",
+			},
+			"proxy_username": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("PRITUNL_PROXY_USERNAME", ""),
+				RequiredWith: []string{"proxy_url"},
+				Description:  "Username of the proxy authentication. Can be set with the `PRITUNL_PROXY_USERNAME` environment variable",
+			},
+			"proxy_password": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				DefaultFunc:  schema.EnvDefaultFunc("PRITUNL_PROXY_PASSWORD", ""),
+				RequiredWith: []string{"proxy_url"},
+				Description:  "Password of the proxy authentication. Can be set with the `PRITUNL_PROXY_PASSWORD` environment variable",
+			},
+			"no_proxy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("PRITUNL_NO_PROXY", ""),
+				RequiredWith: []string{"proxy_url"},
+				Description:  "Comma-separated list of hosts, domains and networks reached without the `proxy_url`, in the `NO_PROXY` format. Can be set with the `PRITUNL_NO_PROXY` environment variable",
+			},
+			"request_cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_REQUEST_CACHE", true),
+				Description: "Cache the lists of objects requested from the Pritunl API during a plan or apply, and share concurrent identical requests. Cached lists are invalidated on every change made by the provider",
+			},
+			"allowed_client_networks": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateCIDRNetwork,
+				},
+				Optional:    true,
+				Description: "Network ranges which server and user networks are allowed to be created in. Defaults to the RFC1918 ranges and the fc00::/7 IPv6 ULA range",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"pritunl_organization": resourceOrganization(),
-			"pritunl_server":       resourceServer(),
-			"pritunl_user":         resourceUser(),
-			"pritunl_link":         resourceLink(),
-			"pritunl_location":     resourceLocation(),
-			"pritunl_route":        resourceRoute(),
-			"pritunl_host":         resourceHost(),
+			"pritunl_organization":  resourceOrganization(),
+			"pritunl_server":        resourceServer(),
+			"pritunl_user":          resourceUser(),
+			"pritunl_link":          resourceLink(),
+			"pritunl_location":      resourceLocation(),
+			"pritunl_route":         resourceRoute(),
+			"pritunl_host":          resourceHost(),
+			"pritunl_cluster_host":  resourceClusterHost(),
+			"pritunl_settings":      resourceSettings(),
+			"pritunl_sso":           resourceSSO(),
+			"pritunl_administrator": resourceAdministrator(),
+			"pritunl_server_link":   resourceServerLink(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"pritunl_host":     dataSourceHost(),
-			"pritunl_hosts":    dataSourceHosts(),
-			"pritunl_link":     dataSourceLink(),
-			"pritunl_location": dataSourceLocation(),
+			"pritunl_host":              dataSourceHost(),
+			"pritunl_hosts":             dataSourceHosts(),
+			"pritunl_host_usage":        dataSourceHostUsage(),
+			"pritunl_link":              dataSourceLink(),
+			"pritunl_location":          dataSourceLocation(),
+			"pritunl_available_network": dataSourceAvailableNetwork(),
+			"pritunl_server_output":     dataSourceServerOutput(),
+			"pritunl_server_bandwidth":  dataSourceServerBandwidth(),
+			"pritunl_server_clients":    dataSourceServerClients(),
+			"pritunl_user_audit":        dataSourceUserAudit(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
 }
 
+// providerMeta is passed to resources and data sources as the provider meta.
+// It embeds pritunl.Client, so it can still be used as the API client directly.
+type providerMeta struct {
+	pritunl.Client
+
+	clientNetworks *networkValidator
+	locks          *mutexKV
+}
+
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	url := d.Get("url").(string)
-	token := d.Get("token").(string)
-	secret := d.Get("secret").(string)
+	credentials, err := resolveCredentials(ctx,
+		providerCredentials{
+			URL:    d.Get("url").(string),
+			Token:  d.Get("token").(string),
+			Secret: d.Get("secret").(string),
+		},
+		d.Get("credential_process").(string),
+		d.Get("profile").(string),
+		d.Get("shared_credentials_file").(string),
+	)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	if credentials.URL == "" {
+		return nil, diag.Errorf("url is required, set it with the provider attribute, the PRITUNL_URL environment variable, a profile or the credential_process")
+	}
+
+	url, token, secret := credentials.URL, credentials.Token, credentials.Secret
+	authMode := d.Get("auth_mode").(string)
 	insecure := d.Get("insecure").(bool)
 	connectionCheck := d.Get("connection_check").(bool)
+	requestCache := d.Get("request_cache").(bool)
+	maxConcurrentRequests := d.Get("max_concurrent_requests").(int)
+
+	allowedClientNetworks := make([]string, 0)
+	for _, v := range d.Get("allowed_client_networks").([]interface{}) {
+		allowedClientNetworks = append(allowedClientNetworks, v.(string))
+	}
+	if len(allowedClientNetworks) == 0 {
+		allowedClientNetworks = defaultAllowedClientNetworks
+	}
+
+	clientNetworks, err := newNetworkValidator(allowedClientNetworks)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	tlsConfig, err := providerTLSConfig(d)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	proxy, err := pritunl.ProxyOptions{
+		URL:      d.Get("proxy_url").(string),
+		Username: d.Get("proxy_username").(string),
+		Password: d.Get("proxy_password").(string),
+		NoProxy:  d.Get("no_proxy").(string),
+	}.Func()
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	options := []pritunl.ClientOption{
+		pritunl.WithMaxConcurrentRequests(maxConcurrentRequests),
+		pritunl.WithTLSConfig(tlsConfig),
+		pritunl.WithProxy(proxy),
+	}
 
-	apiClient := pritunl.NewClient(url, token, secret, insecure)
+	switch authMode {
+	case "session":
+		username := d.Get("username").(string)
+		password := d.Get("password").(string)
+		if username == "" || password == "" {
+			return nil, diag.Errorf("username and password are required with the %q auth_mode", authMode)
+		}
+		options = append(options, pritunl.WithSessionAuth(username, password))
+	default:
+		if token == "" || secret == "" {
+			return nil, diag.Errorf("token and secret are required with the %q auth_mode", authMode)
+		}
+	}
+
+	apiClient := pritunl.NewClient(url, token, secret, insecure, options...)
+	if requestCache {
+		apiClient = pritunl.NewCachingClient(apiClient)
+	}
 
 	if connectionCheck {
 		// execute test api call to ensure that provided credentials are valid and pritunl api works
@@ -73,5 +293,45 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		}
 	}
 
-	return apiClient, nil
+	return &providerMeta{
+		Client:         apiClient,
+		clientNetworks: clientNetworks,
+		locks:          newMutexKV(),
+	}, nil
+}
+
+// providerTLSConfig builds the TLS configuration of the API client from the provider attributes
+func providerTLSConfig(d *schema.ResourceData) (*tls.Config, error) {
+	options := pritunl.TLSOptions{
+		Insecure:      d.Get("insecure").(bool),
+		CACertPEM:     []byte(d.Get("ca_cert_pem").(string)),
+		ClientCertPEM: []byte(d.Get("client_cert_pem").(string)),
+		ClientKeyPEM:  []byte(d.Get("client_key_pem").(string)),
+		ServerName:    d.Get("tls_server_name").(string),
+	}
+
+	if path := d.Get("ca_cert_file").(string); path != "" {
+		// ConflictsWith only checks the configuration, not the environment variables
+		if len(options.CACertPEM) > 0 {
+			return nil, fmt.Errorf("only one of ca_cert_pem and ca_cert_file can be set, including the PRITUNL_CA_CERT_PEM and PRITUNL_CA_CERT_FILE environment variables")
+		}
+
+		caCertPEM, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_cert_file: %s", err)
+		}
+		options.CACertPEM = caCertPEM
+	}
+
+	return options.Config()
+}
+
+// clientNetworkValidator returns the validator of client networks configured for the provider
+func clientNetworkValidator(meta interface{}) *networkValidator {
+	if m, ok := meta.(*providerMeta); ok && m.clientNetworks != nil {
+		return m.clientNetworks
+	}
+
+	validator, _ := newNetworkValidator(defaultAllowedClientNetworks)
+	return validator
 }
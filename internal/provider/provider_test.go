@@ -1,13 +1,17 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -30,7 +34,12 @@ func TestMain(m *testing.M) {
 	secret := os.Getenv("PRITUNL_SECRET")
 	insecure, _ := strconv.ParseBool(os.Getenv("PRITUNL_INSECURE"))
 
-	testClient = pritunl.NewClient(url, token, secret, insecure)
+	var options []pritunl.ClientOption
+	if os.Getenv("PRITUNL_AUTH_MODE") == "session" {
+		options = append(options, pritunl.WithSessionAuth(os.Getenv("PRITUNL_USERNAME"), os.Getenv("PRITUNL_PASSWORD")))
+	}
+
+	testClient = pritunl.NewClient(url, token, secret, insecure, options...)
 	err := testClient.TestApiCall()
 	if err != nil {
 		panic(err)
@@ -45,6 +54,13 @@ func preCheck(t *testing.T) {
 		"PRITUNL_TOKEN",
 		"PRITUNL_SECRET",
 	}
+	if os.Getenv("PRITUNL_AUTH_MODE") == "session" {
+		variables = []string{
+			"PRITUNL_URL",
+			"PRITUNL_USERNAME",
+			"PRITUNL_PASSWORD",
+		}
+	}
 
 	for _, variable := range variables {
 		value := os.Getenv(variable)
@@ -85,3 +101,44 @@ func pritunlUserImportStep(name string) resource.TestStep {
 
 	return step
 }
+
+func TestProviderTLSConfigCACertSources(t *testing.T) {
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caCertFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Setenv("PRITUNL_CA_CERT_FILE", caCertFile)
+
+	t.Run("reads the file from the environment variable", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{})
+
+		_, err := providerTLSConfig(d)
+		if err == nil || !strings.Contains(err.Error(), "no valid PEM encoded certificates") {
+			t.Fatalf("expected the certificates of the file to be parsed, got %v", err)
+		}
+	})
+
+	t.Run("refuses an explicit ca_cert_pem with the environment variable", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+			"ca_cert_pem": "-----BEGIN CERTIFICATE-----",
+		})
+
+		_, err := providerTLSConfig(d)
+		if err == nil || !strings.Contains(err.Error(), "only one of ca_cert_pem and ca_cert_file") {
+			t.Fatalf("expected the conflict to be refused, got %v", err)
+		}
+	})
+}
+
+// applyTestResourceData builds the resource data of the raw configuration and applies the function of the resource to it,
+// failing the test on an error
+func applyTestResourceData(t *testing.T, r *schema.Resource, raw map[string]interface{}, apply func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics, meta interface{}) *schema.ResourceData {
+	t.Helper()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	if diags := apply(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	return d
+}
@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+func resourceAdministrator() *schema.Resource {
+	return &schema.Resource{
+		Description: "The administrator resource manages an account of the Pritunl web console and its API key. " +
+			"The last enabled super user can't be demoted, disabled or deleted, so the web console can't be locked out. " +
+			"Terraform doesn't check the plans destroying a resource, so the deletion is only refused at apply time: " +
+			"set `lifecycle { prevent_destroy = true }` on the super users to refuse it when planning.",
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Username of the administrator",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password of the administrator. It's not returned by the API, so changes made outside of Terraform are not detected",
+			},
+			"super_user": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Allow the administrator to manage the other administrators and the settings",
+			},
+			"disabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Disable the administrator",
+			},
+			"auth_api": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Allow the administrator to use the API with its token and secret",
+			},
+			"otp_auth": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Require a two-step authentication code on login",
+			},
+			"yubikey_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "ID of the YubiKey required on login",
+			},
+			"api_key_rotation_triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values which regenerate the API token and secret when they change, e.g. a timestamp",
+			},
+			"token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "API token of the administrator",
+			},
+			"secret": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "API secret of the administrator",
+			},
+			"otp_secret": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Two-step authentication secret of the administrator",
+			},
+			"default": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether it's the default administrator created with Pritunl, which still has the default password",
+			},
+		},
+		CustomizeDiff: customdiff.All(
+			resourceAdministratorCustomizeSuperUser,
+			resourceAdministratorCustomizeAPIKey,
+		),
+		CreateContext: resourceCreateAdministrator,
+		ReadContext:   resourceReadAdministrator,
+		UpdateContext: resourceUpdateAdministrator,
+		DeleteContext: resourceDeleteAdministrator,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+// resourceAdministratorCustomizeSuperUser refuses at plan time to demote or disable the last enabled super user.
+// Terraform doesn't customize the plans destroying a resource, so the deletion is only refused at apply time.
+// The check is repeated at apply time, since several super users can be demoted in the same plan.
+func resourceAdministratorCustomizeSuperUser(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !demotesSuperUser(d) {
+		return nil
+	}
+
+	return checkOtherSuperUser(meta, d.Id(), d.Get("username").(string), "demoted or disabled")
+}
+
+// changeGetter is implemented by both the resource data of the apply and the resource diff of the plan
+type changeGetter interface {
+	GetChange(key string) (interface{}, interface{})
+}
+
+// demotesSuperUser reports whether the change demotes or disables an enabled super user
+func demotesSuperUser(d changeGetter) bool {
+	oldSuperUser, newSuperUser := d.GetChange("super_user")
+	oldDisabled, newDisabled := d.GetChange("disabled")
+
+	return oldSuperUser.(bool) && !oldDisabled.(bool) && !(newSuperUser.(bool) && !newDisabled.(bool))
+}
+
+// resourceAdministratorCustomizeAPIKey plans the new API key when the rotation triggers change
+func resourceAdministratorCustomizeAPIKey(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange("api_key_rotation_triggers") {
+		return nil
+	}
+
+	if err := d.SetNewComputed("token"); err != nil {
+		return err
+	}
+
+	return d.SetNewComputed("secret")
+}
+
+// checkOtherSuperUser returns an error when no enabled super user other than the administrator is left
+func checkOtherSuperUser(meta interface{}, id string, username string, action string) error {
+	apiClient := meta.(pritunl.Client)
+
+	// the administrators changed by the other resources of the same apply must be counted
+	apiClient.InvalidateCache("/admin")
+	administrators, err := apiClient.GetAdministrators()
+	if err != nil {
+		return err
+	}
+
+	for _, administrator := range administrators {
+		if administrator.ID != id && administrator.SuperUser && !administrator.Disabled {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("administrator %s is the last enabled super user, it can't be %s", username, action)
+}
+
+func resourceReadAdministrator(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	administrator, err := apiClient.GetAdministrator(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("username", administrator.Username)
+	d.Set("super_user", administrator.SuperUser)
+	d.Set("disabled", administrator.Disabled)
+	d.Set("auth_api", administrator.AuthAPI)
+	d.Set("otp_auth", administrator.OtpAuth)
+	d.Set("yubikey_id", administrator.YubikeyID)
+	d.Set("token", administrator.Token)
+	d.Set("secret", administrator.Secret)
+	d.Set("otp_secret", administrator.OtpSecret)
+	d.Set("default", administrator.Default)
+
+	return nil
+}
+
+func resourceCreateAdministrator(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	administrator, err := apiClient.CreateAdministrator(pritunl.Administrator{
+		Username:  d.Get("username").(string),
+		Password:  d.Get("password").(string),
+		SuperUser: d.Get("super_user").(bool),
+		Disabled:  d.Get("disabled").(bool),
+		AuthAPI:   d.Get("auth_api").(bool),
+		OtpAuth:   d.Get("otp_auth").(bool),
+		YubikeyID: d.Get("yubikey_id").(string),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(administrator.ID)
+
+	return resourceReadAdministrator(ctx, d, meta)
+}
+
+func resourceUpdateAdministrator(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	if d.HasChanges("username", "password", "super_user", "disabled", "auth_api", "otp_auth", "yubikey_id") {
+		if demotesSuperUser(d) {
+			unlock := lockSuperUsers(meta)
+			defer unlock()
+
+			err := checkOtherSuperUser(meta, d.Id(), d.Get("username").(string), "demoted or disabled")
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+
+		administrator, err := apiClient.GetAdministrator(d.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		administrator.Username = d.Get("username").(string)
+		administrator.SuperUser = d.Get("super_user").(bool)
+		administrator.Disabled = d.Get("disabled").(bool)
+		administrator.AuthAPI = d.Get("auth_api").(bool)
+		administrator.OtpAuth = d.Get("otp_auth").(bool)
+		administrator.YubikeyID = d.Get("yubikey_id").(string)
+		if d.HasChange("password") {
+			administrator.Password = d.Get("password").(string)
+		}
+
+		err = apiClient.UpdateAdministrator(d.Id(), administrator)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("api_key_rotation_triggers") {
+		_, err := apiClient.RegenerateAdministratorAPIKey(d.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceReadAdministrator(ctx, d, meta)
+}
+
+func resourceDeleteAdministrator(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	if d.Get("super_user").(bool) && !d.Get("disabled").(bool) {
+		unlock := lockSuperUsers(meta)
+		defer unlock()
+
+		err := checkOtherSuperUser(meta, d.Id(), d.Get("username").(string), "deleted")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err := apiClient.DeleteAdministrator(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestResourceAdministratorFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	apiClient := fake.NewClient()
+
+	d := applyTestResourceData(t, resourceAdministrator(), map[string]interface{}{
+		"username": "alice",
+		"password": "correct horse battery staple",
+		"auth_api": true,
+	}, resourceCreateAdministrator, apiClient)
+
+	if !d.Get("super_user").(bool) || !d.Get("auth_api").(bool) {
+		t.Fatalf("expected an API enabled super user")
+	}
+
+	token, secret := d.Get("token").(string), d.Get("secret").(string)
+	if token == "" || secret == "" {
+		t.Fatalf("expected the API key to be read")
+	}
+
+	t.Run("regenerates the API key when the triggers change", func(t *testing.T) {
+		updated := schema.TestResourceDataRaw(t, resourceAdministrator().Schema, map[string]interface{}{
+			"username":                  "alice",
+			"password":                  "correct horse battery staple",
+			"auth_api":                  true,
+			"api_key_rotation_triggers": map[string]interface{}{"rotated": "2026-10-19"},
+		})
+		updated.SetId(d.Id())
+
+		if diags := resourceUpdateAdministrator(context.Background(), updated, apiClient); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if updated.Get("token").(string) == token || updated.Get("secret").(string) == secret {
+			t.Fatalf("expected a new API key")
+		}
+		if updated.Get("username").(string) != "alice" {
+			t.Fatalf("expected the administrator to be kept, got %s", updated.Get("username"))
+		}
+	})
+
+	t.Run("refuses to demote the last super user", func(t *testing.T) {
+		diff := func() error {
+			_, err := resourceAdministrator().SimpleDiff(context.Background(), d.State(), terraform.NewResourceConfigRaw(map[string]interface{}{
+				"username":   "alice",
+				"super_user": false,
+			}), apiClient)
+			return err
+		}
+
+		if err := diff(); err == nil || !strings.Contains(err.Error(), "administrator alice is the last enabled super user") {
+			t.Fatalf("expected the demotion to be refused, got %v", err)
+		}
+
+		fake.AddAdministrator(pritunl.Administrator{Username: "bob", SuperUser: true, Disabled: true})
+		if err := diff(); err == nil {
+			t.Fatalf("expected a disabled super user not to count")
+		}
+
+		fake.AddAdministrator(pritunl.Administrator{Username: "carol", SuperUser: true})
+		if err := diff(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	})
+
+	t.Run("refuses to delete the last super user", func(t *testing.T) {
+		other := pritunltest.NewTestServer(t)
+
+		last := other.AddAdministrator(pritunl.Administrator{Username: "dave", SuperUser: true})
+		other.AddAdministrator(pritunl.Administrator{Username: "erin", SuperUser: false})
+
+		state := resourceAdministrator().Data(nil)
+		state.SetId(last.ID)
+		state.Set("username", "dave")
+		state.Set("super_user", true)
+
+		diags := resourceDeleteAdministrator(context.Background(), state, other.NewClient())
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, "it can't be deleted") {
+			t.Fatalf("expected the deletion to be refused, got %v", diags)
+		}
+		if other.Requests("DELETE /admin/{id}") != 0 {
+			t.Fatalf("expected the administrator to be kept")
+		}
+	})
+	t.Run("refuses to demote both of the last super users in the same apply", func(t *testing.T) {
+		other := pritunltest.NewTestServer(t)
+		other.SetLatency(10 * time.Millisecond)
+
+		otherClient := other.NewClient()
+		frank := other.AddAdministrator(pritunl.Administrator{Username: "frank", SuperUser: true})
+		grace := other.AddAdministrator(pritunl.Administrator{Username: "grace", SuperUser: true})
+
+		// both demotions pass the plan, each of them still sees the other super user
+		demote := func(administrator pritunl.Administrator) func() diag.Diagnostics {
+			username := administrator.Username
+
+			current := resourceAdministrator().Data(nil)
+			current.SetId(administrator.ID)
+			current.Set("username", username)
+			current.Set("super_user", true)
+			state := current.State()
+
+			diff, err := resourceAdministrator().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+				"username":   username,
+				"super_user": false,
+			}), otherClient)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			d, err := schema.InternalMap(resourceAdministrator().Schema).Data(state, diff)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			return func() diag.Diagnostics {
+				return resourceUpdateAdministrator(context.Background(), d, otherClient)
+			}
+		}
+
+		updates := []func() diag.Diagnostics{demote(frank), demote(grace)}
+
+		var wg sync.WaitGroup
+		results := make([]diag.Diagnostics, len(updates))
+		for i, update := range updates {
+			wg.Add(1)
+			go func(i int, update func() diag.Diagnostics) {
+				defer wg.Done()
+				results[i] = update()
+			}(i, update)
+		}
+		wg.Wait()
+
+		refused := 0
+		for _, diags := range results {
+			if diags.HasError() {
+				if !strings.Contains(diags[0].Summary, "is the last enabled super user") {
+					t.Fatalf("unexpected error: %v", diags)
+				}
+				refused++
+			}
+		}
+		if refused != 1 {
+			t.Fatalf("expected one of the demotions to be refused, got %d refused", refused)
+		}
+	})
+}
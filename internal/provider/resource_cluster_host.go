@@ -0,0 +1,272 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+// clusterHostFields maps the settable attributes of the resource to the fields of the host
+var clusterHostFields = map[string]func(host *pritunl.Host) *string{
+	"name":               func(host *pritunl.Host) *string { return &host.Name },
+	"public_address":     func(host *pritunl.Host) *string { return &host.PublicAddress },
+	"public_address6":    func(host *pritunl.Host) *string { return &host.PublicAddress6 },
+	"local_address":      func(host *pritunl.Host) *string { return &host.LocalAddress },
+	"local_address6":     func(host *pritunl.Host) *string { return &host.LocalAddress6 },
+	"link_address":       func(host *pritunl.Host) *string { return &host.LinkAddress },
+	"sync_address":       func(host *pritunl.Host) *string { return &host.SyncAddress },
+	"routed_subnet6":     func(host *pritunl.Host) *string { return &host.RoutedSubnet6 },
+	"routed_subnet6_wg":  func(host *pritunl.Host) *string { return &host.RoutedSubnet6WG },
+	"availability_group": func(host *pritunl.Host) *string { return &host.AvailabilityGroup },
+}
+
+// isClusterHostAddress reports whether the attribute overrides an address detected by the host
+func isClusterHostAddress(key string) bool {
+	return strings.HasSuffix(key, "_address") || strings.HasSuffix(key, "_address6")
+}
+
+func resourceClusterHost() *schema.Resource {
+	return &schema.Resource{
+		Description: "The cluster host resource adopts an existing node of the Pritunl cluster and manages its addresses and availability group. " +
+			"Hosts register themselves when the Pritunl service starts, so the resource can't create them. " +
+			"The address overrides left unset are removed from the adopted host, so the detected addresses are used.",
+		Schema: map[string]*schema.Schema{
+			"host_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"host_id", "hostname"},
+				Description:  "ID of the host to adopt",
+			},
+			"hostname": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"host_id", "hostname"},
+				Description:  "Hostname of the host to adopt",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the host",
+			},
+			"public_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Public IP address or domain name of the host, overrides the detected one which is used when unset",
+			},
+			"public_address6": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Public IPv6 address or domain name of the host, overrides the detected one which is used when unset",
+			},
+			"local_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Local network address of the host, overrides the detected one which is used when unset",
+			},
+			"local_address6": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Local IPv6 network address of the host, overrides the detected one which is used when unset",
+			},
+			"link_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "IP address or domain used when linked servers connect to a linked server on this host, the public address is used when unset",
+			},
+			"sync_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "IP address or domain used by users when syncing configuration, the public address is used when unset. This is needed when using a load balancer.",
+			},
+			"routed_subnet6": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "IPv6 subnet that is routed to the host",
+			},
+			"routed_subnet6_wg": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "IPv6 WG subnet that is routed to the host",
+			},
+			"availability_group": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Availability group of the host. Replicated servers will only be replicated to a group of hosts in the same availability group",
+			},
+			"remove_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Remove the host from the cluster on destroy, only offline hosts can be removed. The host is only forgotten by Terraform when it's false",
+			},
+			"public_addr": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Public address in use by the host",
+			},
+			"public_addr6": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Public IPv6 address in use by the host",
+			},
+			"local_addr": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Local address in use by the host",
+			},
+			"local_addr6": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Local IPv6 address in use by the host",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the host",
+			},
+		},
+		CreateContext: resourceCreateClusterHost,
+		ReadContext:   resourceReadClusterHost,
+		UpdateContext: resourceUpdateClusterHost,
+		DeleteContext: resourceDeleteClusterHost,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceReadClusterHost(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	host, err := apiClient.GetClusterHost(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("host_id", host.ID)
+	d.Set("hostname", host.Hostname)
+	for key, field := range clusterHostFields {
+		d.Set(key, *field(host))
+	}
+	d.Set("public_addr", host.PublicAddr)
+	d.Set("public_addr6", host.PublicAddr6)
+	d.Set("local_addr", host.LocalAddr)
+	d.Set("local_addr6", host.LocalAddr6)
+	d.Set("status", host.Status)
+
+	return nil
+}
+
+func resourceCreateClusterHost(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	var host *pritunl.Host
+	if id, ok := d.GetOk("host_id"); ok {
+		found, err := apiClient.GetClusterHost(id.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		host = found
+	} else {
+		found, err := findHostByHostname(meta, d.Get("hostname").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		host = &found
+	}
+
+	d.SetId(host.ID)
+
+	changed := false
+	for key, field := range clusterHostFields {
+		value, ok := d.GetOk(key)
+		// the address overrides aren't computed, the ones left unset are removed from the adopted host
+		if !ok && !isClusterHostAddress(key) {
+			continue
+		}
+		if value.(string) != *field(host) {
+			*field(host) = value.(string)
+			changed = true
+		}
+	}
+
+	if changed {
+		err := apiClient.UpdateClusterHost(host.ID, host)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceReadClusterHost(ctx, d, meta)
+}
+
+func resourceUpdateClusterHost(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	host, err := apiClient.GetClusterHost(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	changed := false
+	for key, field := range clusterHostFields {
+		if d.HasChange(key) {
+			*field(host) = d.Get(key).(string)
+			changed = true
+		}
+	}
+
+	if changed {
+		err = apiClient.UpdateClusterHost(d.Id(), host)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceReadClusterHost(ctx, d, meta)
+}
+
+func resourceDeleteClusterHost(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	if !d.Get("remove_on_destroy").(bool) {
+		d.SetId("")
+		return nil
+	}
+
+	host, err := apiClient.GetClusterHost(d.Id())
+	// the host was already removed outside of Terraform
+	if errors.Is(err, pritunl.ErrNotFound) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// an online host registers itself again, it must be stopped or terminated first
+	if host.Status != "offline" {
+		return diag.Errorf("host %s is %s, only offline hosts can be removed from the cluster", host.Hostname, host.Status)
+	}
+
+	err = apiClient.DeleteClusterHost(d.Id())
+	if err != nil && !errors.Is(err, pritunl.ErrNotFound) {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
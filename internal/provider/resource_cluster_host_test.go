@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestResourceClusterHostFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	apiClient := fake.NewClient()
+	host := fake.AddHost(pritunl.Host{
+		Name:       "node-1",
+		Hostname:   "node-1.internal",
+		PublicAddr: "203.0.113.10",
+		Status:     "online",
+	})
+
+	t.Run("adopts the host by hostname", func(t *testing.T) {
+		d := applyTestResourceData(t, resourceClusterHost(), map[string]interface{}{
+			"hostname":           "node-1.internal",
+			"public_address":     "vpn.example.com",
+			"availability_group": "eu-west-1a",
+		}, resourceCreateClusterHost, apiClient)
+
+		if d.Id() != host.ID || d.Get("host_id").(string) != host.ID {
+			t.Fatalf("expected the host %s to be adopted, got %s", host.ID, d.Id())
+		}
+		if d.Get("name").(string) != "node-1" {
+			t.Fatalf("expected the name to be read, got %s", d.Get("name"))
+		}
+		if d.Get("public_addr").(string) != "vpn.example.com" || d.Get("availability_group").(string) != "eu-west-1a" {
+			t.Fatalf("expected the host to be updated, got %s and %s", d.Get("public_addr"), d.Get("availability_group"))
+		}
+	})
+
+	t.Run("adopts the host by ID", func(t *testing.T) {
+		// the first host has the public address override set by the first test
+		host := fake.AddHost(pritunl.Host{Hostname: "node-5.internal", Status: "online"})
+		d := schema.TestResourceDataRaw(t, resourceClusterHost().Schema, map[string]interface{}{
+			"host_id": host.ID,
+		})
+
+		updates := fake.Requests("PUT /host/{id}")
+		if diags := resourceCreateClusterHost(context.Background(), d, apiClient); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+
+		if d.Get("hostname").(string) != "node-5.internal" {
+			t.Fatalf("expected the hostname to be read, got %s", d.Get("hostname"))
+		}
+		if fake.Requests("PUT /host/{id}") != updates {
+			t.Fatalf("expected no update without the configured fields")
+		}
+	})
+
+	t.Run("removes the address overrides left unset", func(t *testing.T) {
+		overridden := fake.AddHost(pritunl.Host{
+			Hostname:      "node-4.internal",
+			PublicAddress: "old-vpn.example.com",
+			LinkAddress:   "10.0.0.4",
+			Status:        "online",
+		})
+		d := applyTestResourceData(t, resourceClusterHost(), map[string]interface{}{
+			"host_id":        overridden.ID,
+			"public_address": "vpn.example.com",
+		}, resourceCreateClusterHost, apiClient)
+
+		if d.Get("public_address").(string) != "vpn.example.com" || d.Get("link_address").(string) != "" {
+			t.Fatalf("expected only the configured override to be kept, got %s and %s", d.Get("public_address"), d.Get("link_address"))
+		}
+	})
+
+	t.Run("fails for an unknown hostname", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, resourceClusterHost().Schema, map[string]interface{}{
+			"hostname": "node-2.internal",
+		})
+
+		diags := resourceCreateClusterHost(context.Background(), d, apiClient)
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, "could not find host with a hostname node-2.internal") {
+			t.Fatalf("expected a missing host error, got %v", diags)
+		}
+	})
+
+	t.Run("removes only offline hosts", func(t *testing.T) {
+		d := schema.TestResourceDataRaw(t, resourceClusterHost().Schema, map[string]interface{}{
+			"host_id":           host.ID,
+			"remove_on_destroy": true,
+		})
+		d.SetId(host.ID)
+
+		diags := resourceDeleteClusterHost(context.Background(), d, apiClient)
+		if !diags.HasError() || !strings.Contains(diags[0].Summary, "only offline hosts can be removed") {
+			t.Fatalf("expected the online host to be kept, got %v", diags)
+		}
+
+		offline := fake.AddHost(pritunl.Host{Hostname: "node-3.internal", Status: "offline"})
+		d.SetId(offline.ID)
+
+		if diags := resourceDeleteClusterHost(context.Background(), d, apiClient); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		if _, err := apiClient.GetClusterHost(offline.ID); !errors.Is(err, pritunl.ErrNotFound) {
+			t.Fatalf("expected the offline host to be removed, got %v", err)
+		}
+
+		// the host is already removed, e.g. outside of Terraform
+		d.SetId(offline.ID)
+		if diags := resourceDeleteClusterHost(context.Background(), d, apiClient); diags.HasError() {
+			t.Fatalf("expected the removed host to be forgotten, got %v", diags)
+		}
+		if d.Id() != "" {
+			t.Fatalf("expected the removed host to be forgotten, got %s", d.Id())
+		}
+	})
+}
@@ -12,9 +12,11 @@ func resourceRoute() *schema.Resource {
 		Description: "The route resource allows managing information about a particular Pritunl location route.",
 		Schema: map[string]*schema.Schema{
 			"network": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The network of the resource",
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "The network of the resource",
+				ValidateFunc:     validateRouteNetwork,
+				DiffSuppressFunc: suppressEquivalentNetworkDiff,
 			},
 			"link_id": {
 				Type:        schema.TypeString,
@@ -75,7 +77,7 @@ func resourceUpdateRoute(ctx context.Context, d *schema.ResourceData, meta inter
 	}
 
 	if d.HasChange("network") {
-		route.Network = d.Get("network").(string)
+		route.Network = pritunl.NormalizeNetwork(d.Get("network").(string))
 
 		err = apiClient.UpdateRoute(d.Id(), route)
 		if err != nil {
@@ -90,7 +92,7 @@ func resourceCreateRoute(_ context.Context, d *schema.ResourceData, meta interfa
 	apiClient := meta.(pritunl.Client)
 
 	routeData := pritunl.LocationRoute{
-		Network:    d.Get("network").(string),
+		Network:    pritunl.NormalizeNetwork(d.Get("network").(string)),
 		LinkId:     d.Get("link_id").(string),
 		LocationId: d.Get("location_id").(string),
 	}
@@ -2,12 +2,13 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net"
 	"strings"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
@@ -52,48 +53,29 @@ func resourceServer() *schema.Resource {
 				ValidateFunc: validation.IntBetween(1, 65535),
 			},
 			"network": {
-				Type:        schema.TypeString,
-				Required:    false,
-				Optional:    true,
-				Computed:    true,
-				Description: "Network address for the private network that will be created for clients. This network cannot conflict with any existing local networks",
-
-				ValidateFunc: func(i interface{}, s string) ([]string, []error) {
-					// [10,172,192].[0-255,16-31,168].[0-255].0/[8-24]
-					// 10.0.0.0/8
-					// 172.16.0.0/12
-					// 192.168.0.0/16
-					warnings := make([]string, 0)
-					errors := make([]error, 0)
-
-					_, actualIpNet, err := net.ParseCIDR(i.(string))
-					if err != nil {
-						errors = append(errors, err)
-
-						return warnings, errors
-					}
-
-					expectedIpNets := []string{
-						"10.0.0.0/8",
-						"172.16.0.0/12",
-						"192.168.0.0/16",
-					}
-
-					found := false
-					for _, v := range expectedIpNets {
-						_, expectedIpNet, _ := net.ParseCIDR(v)
-						if actualIpNet.Contains(expectedIpNet.IP) || expectedIpNet.Contains(actualIpNet.IP) {
-							found = true
-							break
-						}
-					}
-
-					if !found {
-						errors = append(errors, fmt.Errorf("provided subnet %s does not belong to expected subnets %s", actualIpNet.String(), strings.Join(expectedIpNets, ", ")))
-					}
-
-					return warnings, errors
-				},
+				Type:             schema.TypeString,
+				Required:         false,
+				Optional:         true,
+				Computed:         true,
+				Description:      "Network address for the private network that will be created for clients. This network cannot conflict with any existing local networks",
+				ValidateFunc:     validateCIDRNetwork,
+				DiffSuppressFunc: suppressEquivalentNetworkDiff,
+			},
+			"network_pool": {
+				Type:          schema.TypeString,
+				Required:      false,
+				Optional:      true,
+				Description:   "Network pool to allocate the server network from. The first subnet of the pool which doesn't overlap with networks of other servers is picked and kept stable until it no longer fits the pool",
+				ConflictsWith: []string{"network"},
+				ValidateFunc:  validateCIDRNetwork,
+			},
+			"network_prefix_length": {
+				Type:         schema.TypeInt,
+				Required:     false,
+				Optional:     true,
+				Default:      24,
+				Description:  "Prefix length of the network allocated from the network_pool",
+				ValidateFunc: validation.IntBetween(8, 30),
 			},
 			"bind_address": {
 				Type:        schema.TypeString,
@@ -105,47 +87,13 @@ func resourceServer() *schema.Resource {
 				},
 			},
 			"network_wg": {
-				Type:         schema.TypeString,
-				Required:     false,
-				Optional:     true,
-				Description:  "Network address for the private network that will be created for clients. This network cannot conflict with any existing local networks",
-				RequiredWith: []string{"port_wg"},
-				ValidateFunc: func(i interface{}, s string) ([]string, []error) {
-					// [10,172,192].[0-255,16-31,168].[0-255].0/[8-24]
-					// 10.0.0.0/8
-					// 172.16.0.0/12
-					// 192.168.0.0/16
-					warnings := make([]string, 0)
-					errors := make([]error, 0)
-
-					_, actualIpNet, err := net.ParseCIDR(i.(string))
-					if err != nil {
-						errors = append(errors, err)
-
-						return warnings, errors
-					}
-
-					expectedIpNets := []string{
-						"10.0.0.0/8",
-						"172.16.0.0/12",
-						"192.168.0.0/16",
-					}
-
-					found := false
-					for _, v := range expectedIpNets {
-						_, expectedIpNet, _ := net.ParseCIDR(v)
-						if actualIpNet.Contains(expectedIpNet.IP) || expectedIpNet.Contains(actualIpNet.IP) {
-							found = true
-							break
-						}
-					}
-
-					if !found {
-						errors = append(errors, fmt.Errorf("provided subnet %s does not belong to expected subnets %s", actualIpNet.String(), strings.Join(expectedIpNets, ", ")))
-					}
-
-					return warnings, errors
-				},
+				Type:             schema.TypeString,
+				Required:         false,
+				Optional:         true,
+				Description:      "Network address for the private network that will be created for clients. This network cannot conflict with any existing local networks",
+				RequiredWith:     []string{"port_wg"},
+				ValidateFunc:     validateCIDRNetwork,
+				DiffSuppressFunc: suppressEquivalentNetworkDiff,
 			},
 			"port_wg": {
 				Type:         schema.TypeInt,
@@ -157,7 +105,7 @@ func resourceServer() *schema.Resource {
 				// TODO: Add validation
 			},
 			"groups": {
-				Type: schema.TypeList,
+				Type: schema.TypeSet,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 					ValidateDiagFunc: func(v interface{}, path cty.Path) diag.Diagnostics {
@@ -181,7 +129,7 @@ func resourceServer() *schema.Resource {
 				Description: "Enter list of groups to allow connections from. Names are case sensitive. If empty all groups will able to connect",
 			},
 			"dns_servers": {
-				Type: schema.TypeList,
+				Type: schema.TypeSet,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 					ValidateFunc: func(i interface{}, s string) ([]string, []error) {
@@ -401,7 +349,7 @@ func resourceServer() *schema.Resource {
 				Description: "Use VXLan for routing client-to-client traffic with replicated servers.",
 			},
 			"organization_ids": {
-				Type: schema.TypeList,
+				Type: schema.TypeSet,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -410,7 +358,7 @@ func resourceServer() *schema.Resource {
 				Description: "The list of attached organizations to the server.",
 			},
 			"host_ids": {
-				Type: schema.TypeList,
+				Type: schema.TypeSet,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -424,12 +372,11 @@ func resourceServer() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"network": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Network address with subnet to route",
-							ValidateFunc: func(i interface{}, s string) ([]string, []error) {
-								return validation.IsCIDR(i, s)
-							},
+							Type:             schema.TypeString,
+							Required:         true,
+							Description:      "Network address with subnet to route",
+							ValidateFunc:     validateRouteNetwork,
+							DiffSuppressFunc: suppressEquivalentNetworkDiff,
 						},
 						"comment": {
 							Type:        schema.TypeString,
@@ -490,6 +437,14 @@ func resourceServer() *schema.Resource {
 				},
 			},
 		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			stateUpgrader(0, resourceServerV0(), listToSet("groups", "dns_servers", "organization_ids", "host_ids")),
+		},
+		CustomizeDiff: customdiff.All(
+			resourceServerCustomizeNetworkPool,
+			resourceServerCustomizeAllowedNetworks,
+		),
 		CreateContext: resourceCreateServer,
 		ReadContext:   resourceReadServer,
 		UpdateContext: resourceUpdateServer,
@@ -504,25 +459,31 @@ func resourceServer() *schema.Resource {
 func resourceReadServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	apiClient := meta.(pritunl.Client)
 
-	server, err := apiClient.GetServer(d.Id())
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	// get organizations
-	organizations, err := apiClient.GetOrganizationsByServer(d.Id())
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	// get routes
-	routes, err := apiClient.GetRoutesByServer(d.Id())
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
-	// get hosts
-	hosts, err := apiClient.GetHostsByServer(d.Id())
+	var (
+		server        *pritunl.Server
+		organizations []pritunl.Organization
+		routes        []pritunl.Route
+		hosts         []pritunl.Host
+	)
+
+	err := fetchParallel(ctx, maxParallelReads,
+		func() (err error) {
+			server, err = apiClient.GetServer(d.Id())
+			return err
+		},
+		func() (err error) {
+			organizations, err = apiClient.GetOrganizationsByServer(d.Id())
+			return err
+		},
+		func() (err error) {
+			routes, err = apiClient.GetRoutesByServer(d.Id())
+			return err
+		},
+		func() (err error) {
+			hosts, err = apiClient.GetHostsByServer(d.Id())
+			return err
+		},
+	)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -568,45 +529,13 @@ func resourceReadServer(ctx context.Context, d *schema.ResourceData, meta interf
 	d.Set("vxlan", server.VxLan)
 	d.Set("status", server.Status)
 
-	if len(organizations) > 0 {
-		organizationsList := make([]string, 0)
-
-		if organizations != nil {
-			for _, organization := range organizations {
-				organizationsList = append(organizationsList, organization.ID)
-			}
-		}
-
-		declaredOrganizations, ok := d.Get("organization_ids").([]interface{})
-		if !ok {
-			return diag.Errorf("failed to parse organization_ids for the server: %s", server.Name)
-		}
-
-		if len(declaredOrganizations) > 0 {
-			organizationsList = matchStringEntitiesWithSchema(organizationsList, declaredOrganizations)
-		}
-
-		d.Set("organization_ids", organizationsList)
+	organizationsList := make([]string, 0)
+	for _, organization := range organizations {
+		organizationsList = append(organizationsList, organization.ID)
 	}
+	d.Set("organization_ids", organizationsList)
 
-	if len(server.Groups) > 0 {
-		groupsList := make([]string, 0)
-
-		for _, group := range server.Groups {
-			groupsList = append(groupsList, group)
-		}
-
-		declaredGroups, ok := d.Get("groups").([]interface{})
-		if !ok {
-			return diag.Errorf("failed to parse groups for the server: %s", server.Name)
-		}
-
-		if len(declaredGroups) > 0 {
-			groupsList = matchStringEntitiesWithSchema(groupsList, declaredGroups)
-		}
-
-		d.Set("groups", groupsList)
-	}
+	d.Set("groups", server.Groups)
 
 	if len(routes) > 0 {
 		declaredRoutes, ok := d.Get("route").([]interface{})
@@ -621,30 +550,77 @@ func resourceReadServer(ctx context.Context, d *schema.ResourceData, meta interf
 		d.Set("route", flattenRoutesData(routes))
 	}
 
-	if len(hosts) > 0 {
-		hostsList := make([]string, 0)
+	hostsList := make([]string, 0)
+	for _, host := range hosts {
+		hostsList = append(hostsList, host.ID)
+	}
+	d.Set("host_ids", hostsList)
 
-		if hosts != nil {
-			for _, host := range hosts {
-				hostsList = append(hostsList, host.ID)
-			}
+	return nil
+}
+
+func resourceServerCustomizeNetworkPool(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	pool, ok := d.GetOk("network_pool")
+	if !ok {
+		return nil
+	}
+
+	if d.NewValueKnown("network_pool") && d.NewValueKnown("network_prefix_length") {
+		if _, err := parseNetworkPool(pool.(string), d.Get("network_prefix_length").(int)); err != nil {
+			return err
 		}
+	}
 
-		declaredHosts, ok := d.Get("host_ids").([]interface{})
-		if !ok {
-			return diag.Errorf("failed to parse host_ids for the server: %s", server.Name)
+	// keep the allocated network stable while it still fits the pool
+	network := d.Get("network").(string)
+	if network == "" || !networkFitsPool(network, pool.(string), d.Get("network_prefix_length").(int)) {
+		return d.SetNewComputed("network")
+	}
+
+	return nil
+}
+
+func resourceServerCustomizeAllowedNetworks(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	validator := clientNetworkValidator(meta)
+	ipv6 := d.Get("ipv6").(bool)
+
+	for _, key := range []string{"network", "network_wg", "network_pool"} {
+		// the networks already saved, e.g. assigned by Pritunl, are kept even when they aren't allowed
+		if !d.NewValueKnown(key) || !d.HasChange(key) {
+			continue
 		}
 
-		if len(declaredHosts) > 0 {
-			hostsList = matchStringEntitiesWithSchema(hostsList, declaredHosts)
+		network := d.Get(key).(string)
+		if network == "" {
+			continue
 		}
 
-		d.Set("host_ids", hostsList)
+		if err := validator.validate(network, ipv6); err != nil {
+			return fmt.Errorf("invalid %s: %s", key, err)
+		}
 	}
 
 	return nil
 }
 
+// allocateServerNetwork picks a network from the network_pool that doesn't overlap networks of other servers.
+// The pool must be locked by the caller until the network is saved, so parallel servers can't get the same network.
+func allocateServerNetwork(d *schema.ResourceData, apiClient pritunl.Client) (string, error) {
+	// the cached list could miss the networks saved by other resources of the pool
+	apiClient.InvalidateCache("/server")
+
+	servers, err := apiClient.GetServers()
+	if err != nil {
+		return "", err
+	}
+
+	return findAvailableNetwork(
+		d.Get("network_pool").(string),
+		d.Get("network_prefix_length").(int),
+		usedServerNetworks(servers, d.Id()),
+	)
+}
+
 func resourceCreateServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	apiClient := meta.(pritunl.Client)
 
@@ -656,8 +632,8 @@ func resourceCreateServer(ctx context.Context, d *schema.ResourceData, meta inte
 		"cipher":             d.Get("cipher"),
 		"hash":               d.Get("hash"),
 		"bind_address":       d.Get("bind_address"),
-		"groups":             d.Get("groups"),
-		"dns_servers":        d.Get("dns_servers"),
+		"groups":             d.Get("groups").(*schema.Set).List(),
+		"dns_servers":        d.Get("dns_servers").(*schema.Set).List(),
 		"network_wg":         d.Get("network_wg"),
 		"port_wg":            d.Get("port_wg"),
 		"sso_auth":           d.Get("sso_auth"),
@@ -691,6 +667,17 @@ func resourceCreateServer(ctx context.Context, d *schema.ResourceData, meta inte
 		"vxlan":              d.Get("vxlan"),
 	}
 
+	if _, ok := d.GetOk("network_pool"); ok {
+		unlockPools := lockNetworkPools(meta)
+		defer unlockPools()
+
+		network, err := allocateServerNetwork(d, apiClient)
+		if err != nil {
+			return diag.Errorf("Error on allocating a network from the network pool: %s", err)
+		}
+		serverData["network"] = network
+	}
+
 	server, err := apiClient.CreateServer(serverData)
 	if err != nil {
 		return diag.FromErr(err)
@@ -698,9 +685,12 @@ func resourceCreateServer(ctx context.Context, d *schema.ResourceData, meta inte
 
 	d.SetId(server.ID)
 
+	unlock := lockServers(meta, d.Id())
+	defer unlock()
+
 	if d.HasChange("organization_ids") {
 		_, newOrgs := d.GetChange("organization_ids")
-		for _, v := range newOrgs.([]interface{}) {
+		for _, v := range newOrgs.(*schema.Set).List() {
 			err = apiClient.AttachOrganizationToServer(v.(string), d.Id())
 			if err != nil {
 				return diag.Errorf("Error on attaching server to the organization: %s", err)
@@ -747,7 +737,7 @@ func resourceCreateServer(ctx context.Context, d *schema.ResourceData, meta inte
 		}
 
 		_, newHosts := d.GetChange("host_ids")
-		for _, v := range newHosts.([]interface{}) {
+		for _, v := range newHosts.(*schema.Set).List() {
 			err = apiClient.AttachHostToServer(v.(string), d.Id())
 			if err != nil {
 				return diag.Errorf("Error on attaching a host to the server: %s", err)
@@ -756,9 +746,9 @@ func resourceCreateServer(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 
 	if d.Get("status").(string) == pritunl.ServerStatusOnline {
-		err = apiClient.StartServer(d.Id())
+		err = startServer(apiClient, d.Id())
 		if err != nil {
-			return diag.Errorf("Error on starting server: %s", err)
+			return serverDiagnostics(apiClient, err)
 		}
 	}
 
@@ -768,6 +758,10 @@ func resourceCreateServer(ctx context.Context, d *schema.ResourceData, meta inte
 func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	apiClient := meta.(pritunl.Client)
 
+	// the server must not be started by another operation until all the changes are applied
+	unlock := lockServers(meta, d.Id())
+	defer unlock()
+
 	server, err := apiClient.GetServer(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -799,6 +793,17 @@ func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta inte
 		server.Network = v.(string)
 	}
 
+	if pool, ok := d.GetOk("network_pool"); ok && !networkFitsPool(server.Network, pool.(string), d.Get("network_prefix_length").(int)) {
+		unlockPools := lockNetworkPools(meta)
+		defer unlockPools()
+
+		network, err := allocateServerNetwork(d, apiClient)
+		if err != nil {
+			return diag.Errorf("Error on allocating a network from the network pool: %s", err)
+		}
+		server.Network = network
+	}
+
 	if d.HasChange("bind_address") {
 		server.BindAddress = d.Get("bind_address").(string)
 	}
@@ -932,7 +937,7 @@ func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta inte
 
 	if d.HasChange("groups") {
 		groups := make([]string, 0)
-		for _, v := range d.Get("groups").([]interface{}) {
+		for _, v := range d.Get("groups").(*schema.Set).List() {
 			groups = append(groups, v.(string))
 		}
 		server.Groups = groups
@@ -940,7 +945,7 @@ func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta inte
 
 	if d.HasChange("dns_servers") {
 		dnsServers := make([]string, 0)
-		for _, v := range d.Get("dns_servers").([]interface{}) {
+		for _, v := range d.Get("dns_servers").(*schema.Set).List() {
 			dnsServers = append(dnsServers, v.(string))
 		}
 		server.DnsServers = dnsServers
@@ -955,17 +960,15 @@ func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta inte
 	if d.HasChange("organization_ids") {
 		oldOrgs, newOrgs := d.GetChange("organization_ids")
 
-		oldOrgsOnly := diffStringLists(oldOrgs.([]interface{}), newOrgs.([]interface{}))
-		for _, v := range oldOrgsOnly {
-			err = apiClient.DetachOrganizationFromServer(v, d.Id())
+		for _, v := range oldOrgs.(*schema.Set).Difference(newOrgs.(*schema.Set)).List() {
+			err = apiClient.DetachOrganizationFromServer(v.(string), d.Id())
 			if err != nil {
 				return diag.Errorf("Error on detaching server to the organization: %s", err)
 			}
 		}
 
-		newOrgsOnly := diffStringLists(newOrgs.([]interface{}), oldOrgs.([]interface{}))
-		for _, v := range newOrgsOnly {
-			err = apiClient.AttachOrganizationToServer(v, d.Id())
+		for _, v := range newOrgs.(*schema.Set).Difference(oldOrgs.(*schema.Set)).List() {
+			err = apiClient.AttachOrganizationToServer(v.(string), d.Id())
 			if err != nil {
 				return diag.Errorf("Error on attaching server to the organization: %s", err)
 			}
@@ -1015,13 +1018,13 @@ func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta inte
 
 	if d.HasChange("host_ids") {
 		oldHosts, newHosts := d.GetChange("host_ids")
-		for _, v := range oldHosts.([]interface{}) {
+		for _, v := range oldHosts.(*schema.Set).List() {
 			err = apiClient.DetachHostFromServer(v.(string), d.Id())
 			if err != nil {
 				return diag.Errorf("Error on detaching server to the organization: %s", err)
 			}
 		}
-		for _, v := range newHosts.([]interface{}) {
+		for _, v := range newHosts.(*schema.Set).List() {
 			err = apiClient.AttachHostToServer(v.(string), d.Id())
 			if err != nil {
 				return diag.Errorf("Error on attaching server to the organization: %s", err)
@@ -1039,9 +1042,9 @@ func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 
 	if shouldServerBeStarted {
-		err = apiClient.StartServer(d.Id())
+		err = startServer(apiClient, d.Id())
 		if err != nil {
-			return diag.Errorf("Error on starting server: %s", err)
+			return serverDiagnostics(apiClient, err)
 		}
 	}
 
@@ -1051,6 +1054,9 @@ func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta inte
 func resourceDeleteServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	apiClient := meta.(pritunl.Client)
 
+	unlock := lockServers(meta, d.Id())
+	defer unlock()
+
 	err := apiClient.DeleteServer(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
@@ -1061,24 +1067,53 @@ func resourceDeleteServer(ctx context.Context, d *schema.ResourceData, meta inte
 	return nil
 }
 
-func diffStringLists(mainList []interface{}, otherList []interface{}) []string {
-	result := make([]string, 0)
-	var found bool
+// serverOutputLines is the number of the output lines attached to the diagnostics of a server which failed to start
+const serverOutputLines = 20
+
+// serverStartError is returned when a server fails to start
+type serverStartError struct {
+	serverId string
+	err      error
+}
+
+func (e *serverStartError) Error() string {
+	return fmt.Sprintf("Error on starting server %s: %s", e.serverId, e.err)
+}
+
+func (e *serverStartError) Unwrap() error {
+	return e.err
+}
+
+func startServer(apiClient pritunl.Client, serverId string) error {
+	err := apiClient.StartServer(serverId)
+	if err != nil {
+		return &serverStartError{serverId: serverId, err: err}
+	}
 
-	for _, i := range mainList {
-		found = false
-		for _, j := range otherList {
-			if i.(string) == j.(string) {
-				found = true
-				break
+	return nil
+}
+
+// serverDiagnostics converts the error to diagnostics, the last output lines of a server which failed to start are the detail
+func serverDiagnostics(apiClient pritunl.Client, err error) diag.Diagnostics {
+	diagnostic := diag.Diagnostic{
+		Severity: diag.Error,
+		Summary:  err.Error(),
+	}
+
+	var startErr *serverStartError
+	if errors.As(err, &startErr) {
+		output, outputErr := apiClient.GetServerOutput(startErr.serverId)
+		if outputErr != nil {
+			diagnostic.Detail = fmt.Sprintf("The output of the server could not be read: %s", outputErr)
+		} else if len(output) > 0 {
+			if len(output) > serverOutputLines {
+				output = output[len(output)-serverOutputLines:]
 			}
-		}
-		if !found {
-			result = append(result, i.(string))
+			diagnostic.Detail = fmt.Sprintf("Last lines of the server output:\n%s", strings.Join(output, "\n"))
 		}
 	}
 
-	return result
+	return diag.Diagnostics{diagnostic}
 }
 
 func flattenRoutesData(routesList []pritunl.Route) []interface{} {
@@ -1090,6 +1125,10 @@ func flattenRoutesData(routesList []pritunl.Route) []interface{} {
 				// skip virtual network route
 				continue
 			}
+			if route.ServerLink {
+				// skip route of a linked server, managed by pritunl_server_link
+				continue
+			}
 
 			routeMap := make(map[string]interface{})
 
@@ -1121,7 +1160,7 @@ func matchRoutesWithSchema(routes []pritunl.Route, declaredRoutes []interface{})
 		declaredRouteMap := declaredRoute.(map[string]interface{})
 
 		for key, route := range routesMap {
-			if route.Network != declaredRouteMap["network"] || route.Nat != declaredRouteMap["nat"] || route.NetGateway != declaredRouteMap["net_gateway"] {
+			if pritunl.NormalizeNetwork(route.Network) != pritunl.NormalizeNetwork(declaredRouteMap["network"].(string)) || route.Nat != declaredRouteMap["nat"] || route.NetGateway != declaredRouteMap["net_gateway"] {
 				continue
 			}
 
@@ -1137,26 +1176,3 @@ func matchRoutesWithSchema(routes []pritunl.Route, declaredRoutes []interface{})
 
 	return result
 }
-
-// This cannot currently be handled efficiently by a DiffSuppressFunc
-// See: https://github.com/hashicorp/terraform-plugin-sdk/issues/477
-func matchStringEntitiesWithSchema(entities []string, declaredEntities []interface{}) []string {
-	if len(declaredEntities) == 0 {
-		return entities
-	}
-
-	result := make([]string, len(declaredEntities))
-
-	for i, declaredEntity := range declaredEntities {
-		for _, entity := range entities {
-			if entity != declaredEntity.(string) {
-				continue
-			}
-
-			result[i] = entity
-			break
-		}
-	}
-
-	return result
-}
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+func resourceServerLink() *schema.Resource {
+	return &schema.Resource{
+		Description: "The server link resource links two servers, so the clients of each server can reach the other one. " +
+			"Pritunl only changes the links of offline servers, so the online servers are stopped and started again.",
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the first server of the link",
+			},
+			"linked_server_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the second server of the link",
+			},
+			"use_local_address": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Connect the servers with the local addresses of their hosts instead of the public ones",
+			},
+		},
+		CreateContext: resourceCreateServerLink,
+		ReadContext:   resourceReadServerLink,
+		UpdateContext: resourceUpdateServerLink,
+		DeleteContext: resourceDeleteServerLink,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportServerLink,
+		},
+	}
+}
+
+func resourceReadServerLink(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	links, err := apiClient.GetServerLinks(d.Get("server_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, link := range links {
+		if link.ID == d.Get("linked_server_id").(string) {
+			d.Set("use_local_address", link.UseLocalAddress)
+			return nil
+		}
+	}
+
+	// the servers were unlinked outside of Terraform
+	d.SetId("")
+
+	return nil
+}
+
+func resourceCreateServerLink(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	serverId := d.Get("server_id").(string)
+	linkedServerId := d.Get("linked_server_id").(string)
+	if serverId == linkedServerId {
+		return diag.Errorf("a server can't be linked to itself, server_id and linked_server_id are both %s", serverId)
+	}
+
+	unlock := lockServers(meta, serverId, linkedServerId)
+	defer unlock()
+
+	err := withServersStopped(apiClient, []string{serverId, linkedServerId}, func() error {
+		return apiClient.AttachServerLink(serverId, linkedServerId, d.Get("use_local_address").(bool))
+	})
+	if err != nil {
+		return serverDiagnostics(apiClient, fmt.Errorf("Error on linking the servers: %w", err))
+	}
+
+	d.SetId(fmt.Sprintf("%s-%s", serverId, linkedServerId))
+
+	return resourceReadServerLink(ctx, d, meta)
+}
+
+func resourceUpdateServerLink(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	serverId := d.Get("server_id").(string)
+	linkedServerId := d.Get("linked_server_id").(string)
+
+	unlock := lockServers(meta, serverId, linkedServerId)
+	defer unlock()
+
+	err := withServersStopped(apiClient, []string{serverId, linkedServerId}, func() error {
+		return apiClient.AttachServerLink(serverId, linkedServerId, d.Get("use_local_address").(bool))
+	})
+	if err != nil {
+		return serverDiagnostics(apiClient, fmt.Errorf("Error on updating the link of the servers: %w", err))
+	}
+
+	return resourceReadServerLink(ctx, d, meta)
+}
+
+func resourceDeleteServerLink(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	serverId := d.Get("server_id").(string)
+	linkedServerId := d.Get("linked_server_id").(string)
+
+	unlock := lockServers(meta, serverId, linkedServerId)
+	defer unlock()
+
+	err := withServersStopped(apiClient, []string{serverId, linkedServerId}, func() error {
+		return apiClient.DetachServerLink(serverId, linkedServerId)
+	})
+	if err != nil {
+		return serverDiagnostics(apiClient, fmt.Errorf("Error on unlinking the servers: %w", err))
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourceImportServerLink(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	attributes := strings.Split(d.Id(), "-")
+	if len(attributes) != 2 {
+		return nil, fmt.Errorf("invalid format: expected ${serverId}-${linkedServerId}, e.g. 60cd0be07723cf3c9114686c-60cd0be17723cf3c91146873, actual id is %s", d.Id())
+	}
+
+	d.Set("server_id", attributes[0])
+	d.Set("linked_server_id", attributes[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// withServersStopped stops the online servers, calls the function and starts the stopped servers again,
+// also when the function fails. The servers must be locked by the caller.
+func withServersStopped(apiClient pritunl.Client, serverIds []string, fn func() error) error {
+	stopped := make([]string, 0, len(serverIds))
+
+	err := func() error {
+		for _, id := range serverIds {
+			server, err := apiClient.GetServer(id)
+			if err != nil {
+				return err
+			}
+			if server.Status != pritunl.ServerStatusOnline {
+				continue
+			}
+
+			err = apiClient.StopServer(id)
+			if err != nil {
+				return fmt.Errorf("Error on stopping server %s: %s", id, err)
+			}
+			stopped = append(stopped, id)
+		}
+
+		return fn()
+	}()
+
+	for _, id := range stopped {
+		if startErr := startServer(apiClient, id); startErr != nil && err == nil {
+			err = startErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestResourceServerLinkFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	apiClient := fake.NewClient()
+	online := fake.AddServer(pritunl.Server{Name: "eu", Network: "10.1.0.0/24", Status: pritunl.ServerStatusOnline})
+	offline := fake.AddServer(pritunl.Server{Name: "us", Network: "10.2.0.0/24"})
+	fake.AddRoute(online.ID, pritunl.Route{Network: "192.168.0.0/24"})
+
+	d := applyTestResourceData(t, resourceServerLink(), map[string]interface{}{
+		"server_id":         online.ID,
+		"linked_server_id":  offline.ID,
+		"use_local_address": true,
+	}, resourceCreateServerLink, apiClient)
+
+	if d.Id() != online.ID+"-"+offline.ID || !d.Get("use_local_address").(bool) {
+		t.Fatalf("expected the link to be read, got %s", d.Id())
+	}
+	if fake.Requests("PUT /server/{id}/operation/{operation}") != 2 {
+		t.Fatalf("expected only the online server to be stopped and started, got %d operations", fake.Requests("PUT /server/{id}/operation/{operation}"))
+	}
+	if server, _ := apiClient.GetServer(online.ID); server.Status != pritunl.ServerStatusOnline {
+		t.Fatalf("expected the online server to be started again, got %s", server.Status)
+	}
+	if server, _ := apiClient.GetServer(offline.ID); server.Status != pritunl.ServerStatusOffline {
+		t.Fatalf("expected the offline server to stay offline, got %s", server.Status)
+	}
+
+	links, err := apiClient.GetServerLinks(offline.ID)
+	if err != nil || len(links) != 1 || links[0].ID != online.ID {
+		t.Fatalf("expected the servers to be linked both ways, got %v (%v)", links, err)
+	}
+
+	routes, err := apiClient.GetRoutesByServer(online.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	flattened := flattenRoutesData(routes)
+	if len(routes) != 2 || len(flattened) != 1 || flattened[0].(map[string]interface{})["network"] != "192.168.0.0/24" {
+		t.Fatalf("expected the route of the linked server to be skipped, got %v", flattened)
+	}
+
+	if diags := resourceDeleteServerLink(context.Background(), d, apiClient); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if links, _ := apiClient.GetServerLinks(online.ID); len(links) != 0 {
+		t.Fatalf("expected the servers to be unlinked, got %v", links)
+	}
+	if server, _ := apiClient.GetServer(online.ID); server.Status != pritunl.ServerStatusOnline {
+		t.Fatalf("expected the online server to be started again, got %s", server.Status)
+	}
+}
+
+func TestResourceServerLinkRestartsOnFailure(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	first := fake.AddServer(pritunl.Server{Name: "eu", Status: pritunl.ServerStatusOnline})
+	second := fake.AddServer(pritunl.Server{Name: "us", Status: pritunl.ServerStatusOnline})
+	fake.FailRequests("PUT /server/{id}/link/{link}", 500)
+
+	d := schema.TestResourceDataRaw(t, resourceServerLink().Schema, map[string]interface{}{
+		"server_id":        first.ID,
+		"linked_server_id": second.ID,
+	})
+
+	diags := resourceCreateServerLink(context.Background(), d, fake.NewClient())
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "Error on linking the servers") {
+		t.Fatalf("expected the link to fail, got %v", diags)
+	}
+
+	for _, id := range []string{first.ID, second.ID} {
+		if server, _ := fake.NewClient().GetServer(id); server.Status != pritunl.ServerStatusOnline {
+			t.Fatalf("expected server %s to be started again, got %s", id, server.Status)
+		}
+	}
+}
+
+func TestResourceImportServerLink(t *testing.T) {
+	d := resourceServerLink().Data(nil)
+
+	d.SetId("60cd0be07723cf3c9114686c")
+	if _, err := resourceImportServerLink(context.Background(), d, nil); err == nil {
+		t.Fatalf("expected an error for an ID without the linked server")
+	}
+
+	d.SetId("60cd0be07723cf3c9114686c-60cd0be17723cf3c91146873")
+	if _, err := resourceImportServerLink(context.Background(), d, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if d.Get("server_id").(string) != "60cd0be07723cf3c9114686c" || d.Get("linked_server_id").(string) != "60cd0be17723cf3c91146873" {
+		t.Fatalf("expected the server IDs to be set, got %s and %s", d.Get("server_id"), d.Get("linked_server_id"))
+	}
+}
@@ -0,0 +1,69 @@
+package provider
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+// resourceServerV0 is the schema of the pritunl_server resource before
+// groups, dns_servers, organization_ids and host_ids were converted to sets
+func resourceServerV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":                  {Type: schema.TypeString, Required: true},
+			"protocol":              {Type: schema.TypeString, Optional: true},
+			"cipher":                {Type: schema.TypeString, Optional: true},
+			"hash":                  {Type: schema.TypeString, Optional: true},
+			"port":                  {Type: schema.TypeInt, Optional: true, Computed: true},
+			"network":               {Type: schema.TypeString, Optional: true, Computed: true},
+			"network_pool":          {Type: schema.TypeString, Optional: true},
+			"network_prefix_length": {Type: schema.TypeInt, Optional: true},
+			"bind_address":          {Type: schema.TypeString, Optional: true},
+			"network_wg":            {Type: schema.TypeString, Optional: true},
+			"port_wg":               {Type: schema.TypeInt, Optional: true},
+			"groups":                {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}, Optional: true},
+			"dns_servers":           {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}, Optional: true},
+			"sso_auth":              {Type: schema.TypeBool, Optional: true},
+			"otp_auth":              {Type: schema.TypeBool, Optional: true},
+			"device_auth":           {Type: schema.TypeBool, Optional: true},
+			"dynamic_firewall":      {Type: schema.TypeBool, Optional: true},
+			"ipv6":                  {Type: schema.TypeBool, Optional: true},
+			"dh_param_bits":         {Type: schema.TypeInt, Optional: true, Computed: true},
+			"ping_interval":         {Type: schema.TypeInt, Optional: true, Computed: true},
+			"ping_timeout":          {Type: schema.TypeInt, Optional: true, Computed: true},
+			"link_ping_interval":    {Type: schema.TypeInt, Optional: true, Computed: true},
+			"link_ping_timeout":     {Type: schema.TypeInt, Optional: true, Computed: true},
+			"session_timeout":       {Type: schema.TypeInt, Optional: true},
+			"inactive_timeout":      {Type: schema.TypeInt, Optional: true},
+			"max_clients":           {Type: schema.TypeInt, Optional: true, Computed: true},
+			"network_mode":          {Type: schema.TypeString, Optional: true},
+			"network_start":         {Type: schema.TypeString, Optional: true},
+			"network_end":           {Type: schema.TypeString, Optional: true},
+			"mss_fix":               {Type: schema.TypeInt, Optional: true},
+			"max_devices":           {Type: schema.TypeInt, Optional: true},
+			"pre_connect_msg":       {Type: schema.TypeString, Optional: true},
+			"allowed_devices":       {Type: schema.TypeString, Optional: true},
+			"search_domain":         {Type: schema.TypeString, Optional: true},
+			"replica_count":         {Type: schema.TypeInt, Optional: true, Computed: true},
+			"multi_device":          {Type: schema.TypeBool, Optional: true},
+			"debug":                 {Type: schema.TypeBool, Optional: true},
+			"restrict_routes":       {Type: schema.TypeBool, Optional: true},
+			"block_outside_dns":     {Type: schema.TypeBool, Optional: true},
+			"dns_mapping":           {Type: schema.TypeBool, Optional: true},
+			"inter_client":          {Type: schema.TypeBool, Optional: true},
+			"vxlan":                 {Type: schema.TypeBool, Optional: true},
+			"organization_ids":      {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}, Optional: true},
+			"host_ids":              {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}, Optional: true, Computed: true},
+			"route": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"network":     {Type: schema.TypeString, Required: true},
+						"comment":     {Type: schema.TypeString, Optional: true},
+						"nat":         {Type: schema.TypeBool, Optional: true, Computed: true},
+						"net_gateway": {Type: schema.TypeBool, Optional: true, Computed: true},
+					},
+				},
+			},
+			"status": {Type: schema.TypeString, Optional: true, Computed: true},
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestResourceServerStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":               "60cd0be07723cf3c911468f0",
+		"name":             "server",
+		"groups":           []interface{}{"admins", "", "developers", "admins"},
+		"dns_servers":      []interface{}{"8.8.8.8"},
+		"organization_ids": []interface{}{"60cd0bfa7723cf3c9114686c", "60cd0bfa7723cf3c9114686c"},
+	}
+
+	expected := map[string]interface{}{
+		"id":               "60cd0be07723cf3c911468f0",
+		"name":             "server",
+		"groups":           []interface{}{"admins", "developers"},
+		"dns_servers":      []interface{}{"8.8.8.8"},
+		"organization_ids": []interface{}{"60cd0bfa7723cf3c9114686c"},
+	}
+
+	actual, err := resourceServer().StateUpgraders[0].Upgrade(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected state %v, got %v", expected, actual)
+	}
+}
+
+func TestResourceUserStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":            "60cd0be07723cf3c911468f0",
+		"groups":        []interface{}{"admins", "admins"},
+		"mac_addresses": []interface{}{"00:11:22:33:44:55", ""},
+		"dns_servers":   []interface{}{"8.8.8.8", "8.8.8.8"},
+	}
+
+	expected := map[string]interface{}{
+		"id":            "60cd0be07723cf3c911468f0",
+		"groups":        []interface{}{"admins"},
+		"mac_addresses": []interface{}{"00:11:22:33:44:55"},
+		// dns_servers is still a list
+		"dns_servers": []interface{}{"8.8.8.8", "8.8.8.8"},
+	}
+
+	actual, err := resourceUser().StateUpgraders[0].Upgrade(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected state %v, got %v", expected, actual)
+	}
+}
@@ -1,15 +1,110 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strconv"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
 )
 
+func TestResourceServerNetworkPoolFromFakeServer(t *testing.T) {
+	t.Run("allocates different networks to servers created in parallel", func(t *testing.T) {
+		fake := pritunltest.NewTestServer(t)
+
+		fake.AddServer(pritunl.Server{Name: "existing", Network: "10.100.0.0/24"})
+		fake.SetLatency(10 * time.Millisecond)
+
+		// both creates share the server list cached before them, e.g. by the refresh
+		apiClient := pritunl.NewCachingClient(fake.NewClient())
+		if _, err := apiClient.GetServers(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		resources := make([]*schema.ResourceData, 2)
+		for i := range resources {
+			resources[i] = schema.TestResourceDataRaw(t, resourceServer().Schema, map[string]interface{}{
+				"name":         fmt.Sprintf("pool-%d", i),
+				"network_pool": "10.100.0.0/16",
+			})
+		}
+
+		diags := make([]diag.Diagnostics, len(resources))
+		var wg sync.WaitGroup
+		for i, d := range resources {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				diags[i] = resourceCreateServer(context.Background(), d, apiClient)
+			}()
+		}
+		wg.Wait()
+
+		networks := make(map[string]bool)
+		for i, d := range resources {
+			if diags[i].HasError() {
+				t.Fatalf("unexpected error: %v", diags[i])
+			}
+			networks[d.Get("network").(string)] = true
+		}
+
+		if len(networks) != 2 || networks["10.100.0.0/24"] || !networks["10.100.1.0/24"] || !networks["10.100.2.0/24"] {
+			t.Fatalf("expected the servers to get the next free networks of the pool, got %v", networks)
+		}
+	})
+
+	t.Run("refuses invalid pools when planning", func(t *testing.T) {
+		testCases := map[string]map[string]interface{}{
+			"network pool 2001:db8::/48 must be an IPv4 network": {
+				"name":         "ipv6",
+				"network_pool": "2001:db8::/48",
+			},
+			"prefix length /16 must be between /20 and /32": {
+				"name":                  "short",
+				"network_pool":          "10.100.0.0/20",
+				"network_prefix_length": 16,
+			},
+		}
+
+		for expected, raw := range testCases {
+			_, err := resourceServer().SimpleDiff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil)
+			if err == nil || !regexp.MustCompile(regexp.QuoteMeta(expected)).MatchString(err.Error()) {
+				t.Errorf("expected %q, got %v", expected, err)
+			}
+		}
+	})
+}
+
+func TestResourceServerAllowedNetworksOnPlan(t *testing.T) {
+	state := resourceServer().Data(nil)
+	state.SetId("60cd0be07723cf3c9114686c")
+	state.Set("name", "assigned")
+	state.Set("network", "100.100.0.0/24")
+
+	diff := func(raw map[string]interface{}) error {
+		_, err := resourceServer().SimpleDiff(context.Background(), state.State(), terraform.NewResourceConfigRaw(raw), nil)
+		return err
+	}
+
+	if err := diff(map[string]interface{}{"name": "renamed"}); err != nil {
+		t.Fatalf("expected the network assigned by Pritunl to be kept, got %s", err)
+	}
+
+	err := diff(map[string]interface{}{"name": "renamed", "network": "100.101.0.0/24"})
+	if err == nil || !regexp.MustCompile("invalid network").MatchString(err.Error()) {
+		t.Fatalf("expected the changed network to be validated, got %v", err)
+	}
+}
+
 func TestAccPritunlServer(t *testing.T) {
 
 	t.Run("creates a server with default configuration", func(t *testing.T) {
@@ -197,14 +292,8 @@ func TestAccPritunlServer(t *testing.T) {
 						resource.TestCheckResourceAttr("pritunl_server.test", "name", serverName),
 						resource.TestCheckResourceAttr("pritunl_organization.test", "name", orgName),
 
-						func(s *terraform.State) error {
-							attachedOrganizationId := s.RootModule().Resources["pritunl_server.test"].Primary.Attributes["organization_ids.0"]
-							organizationId := s.RootModule().Resources["pritunl_organization.test"].Primary.Attributes["id"]
-							if attachedOrganizationId != organizationId {
-								return fmt.Errorf("organization_id is invalid or empty")
-							}
-							return nil
-						},
+						resource.TestCheckResourceAttr("pritunl_server.test", "organization_ids.#", "1"),
+						resource.TestCheckTypeSetElemAttrPair("pritunl_server.test", "organization_ids.*", "pritunl_organization.test", "id"),
 					),
 				},
 				// import test
@@ -218,8 +307,6 @@ func TestAccPritunlServer(t *testing.T) {
 		org1Name := "tfacc-org1"
 		org2Name := "tfacc-org2"
 
-		expectedOrganizationIds := make(map[string]struct{})
-
 		resource.Test(t, resource.TestCase{
 			PreCheck:          func() { preCheck(t) },
 			ProviderFactories: providerFactories,
@@ -232,52 +319,13 @@ func TestAccPritunlServer(t *testing.T) {
 						resource.TestCheckResourceAttr("pritunl_organization.test", "name", org1Name),
 						resource.TestCheckResourceAttr("pritunl_organization.test2", "name", org2Name),
 
-						func(s *terraform.State) error {
-							attachedOrganization1Id := s.RootModule().Resources["pritunl_server.test"].Primary.Attributes["organization_ids.0"]
-							attachedOrganization2Id := s.RootModule().Resources["pritunl_server.test"].Primary.Attributes["organization_ids.1"]
-							organization1Id := s.RootModule().Resources["pritunl_organization.test"].Primary.Attributes["id"]
-							organization2Id := s.RootModule().Resources["pritunl_organization.test2"].Primary.Attributes["id"]
-							expectedOrganizationIds = map[string]struct{}{
-								organization1Id: {},
-								organization2Id: {},
-							}
-
-							if attachedOrganization1Id == attachedOrganization2Id {
-								return fmt.Errorf("first and seconds attached organization_id is the same")
-							}
-
-							if _, ok := expectedOrganizationIds[attachedOrganization1Id]; !ok {
-								return fmt.Errorf("attached organization_id %s doesn't contain in expected organizations list", attachedOrganization1Id)
-							}
-
-							if _, ok := expectedOrganizationIds[attachedOrganization2Id]; !ok {
-								return fmt.Errorf("attached organization_id %s doesn't contain in expected organizations list", attachedOrganization2Id)
-							}
-
-							return nil
-						},
+						resource.TestCheckResourceAttr("pritunl_server.test", "organization_ids.#", "2"),
+						resource.TestCheckTypeSetElemAttrPair("pritunl_server.test", "organization_ids.*", "pritunl_organization.test", "id"),
+						resource.TestCheckTypeSetElemAttrPair("pritunl_server.test", "organization_ids.*", "pritunl_organization.test2", "id"),
 					),
 				},
-				// import test (custom import that ignores order of organization IDs)
-				{
-					ResourceName: "pritunl_server.test",
-					ImportStateCheck: func(states []*terraform.InstanceState) error {
-						importedOrganization1Id := states[0].Attributes["organization_ids.0"]
-						importedOrganization2Id := states[0].Attributes["organization_ids.1"]
-
-						if _, ok := expectedOrganizationIds[importedOrganization1Id]; !ok {
-							return fmt.Errorf("imported organization_id %s doesn't contain in expected organizations list", importedOrganization1Id)
-						}
-
-						if _, ok := expectedOrganizationIds[importedOrganization2Id]; !ok {
-							return fmt.Errorf("imported organization_id %s doesn't contain in expected organizations list", importedOrganization2Id)
-						}
-
-						return nil
-					},
-					ImportState:       true,
-					ImportStateVerify: false,
-				},
+				// import test
+				importStep("pritunl_server.test"),
 			},
 		})
 	})
@@ -420,6 +468,31 @@ func TestAccPritunlServer(t *testing.T) {
 			})
 		})
 
+		t.Run("due to a network outside of allowed_client_networks", func(t *testing.T) {
+			serverName := "tfacc-server1"
+			port := 11111
+			cgnatNetwork := "100.100.0.0/24"
+
+			resource.Test(t, resource.TestCase{
+				PreCheck:          func() { preCheck(t) },
+				ProviderFactories: providerFactories,
+				CheckDestroy:      testPritunlServerDestroy,
+				Steps: []resource.TestStep{
+					{
+						Config:      testGetServerConfigWithNetworkAndPort(serverName, cgnatNetwork, port),
+						ExpectError: regexp.MustCompile(fmt.Sprintf("provided subnet %s does not belong to expected subnets", cgnatNetwork)),
+					},
+					{
+						Config: testPritunlProviderConfigWithAllowedClientNetworks("100.64.0.0/10") +
+							testGetServerConfigWithNetworkAndPort(serverName, cgnatNetwork, port),
+						Check: resource.ComposeTestCheckFunc(
+							resource.TestCheckResourceAttr("pritunl_server.test", "network", cgnatNetwork),
+						),
+					},
+				},
+			})
+		})
+
 		t.Run("due to an invalid route", func(t *testing.T) {
 			serverName := "tfacc-server1"
 			invalidRouteNetwork := "10.100.0.2"
@@ -480,14 +553,7 @@ func TestAccPritunlServer(t *testing.T) {
 						Check: resource.ComposeTestCheckFunc(
 							resource.TestCheckResourceAttr("pritunl_server.test", "name", serverName),
 
-							func(s *terraform.State) error {
-								groupName := s.RootModule().Resources["pritunl_server.test"].Primary.Attributes["groups.0"]
-								if groupName != correctGroupName {
-									return fmt.Errorf("group name mismatch")
-								}
-
-								return nil
-							},
+							resource.TestCheckTypeSetElemAttr("pritunl_server.test", "groups.*", correctGroupName),
 						),
 					},
 					// import test
@@ -643,6 +709,14 @@ func testGetServerConfigWithBindAddress(name, network, bindAddress string, port
 	`, name, network, bindAddress, port)
 }
 
+func testPritunlProviderConfigWithAllowedClientNetworks(network string) string {
+	return fmt.Sprintf(`
+		provider "pritunl" {
+			allowed_client_networks = ["%[1]s"]
+		}
+	`, network)
+}
+
 func testPritunlServerConfigWithGroups(name string, groupName string) string {
 	return fmt.Sprintf(`
 		resource "pritunl_server" "test" {
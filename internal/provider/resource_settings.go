@@ -0,0 +1,262 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+// settingsID is the ID of the pritunl_settings singleton
+const settingsID = "settings"
+
+// settingsAttributes are the attributes of pritunl_settings stored under the same keys in the Pritunl settings
+var settingsAttributes = []string{
+	"public_address",
+	"public_address6",
+	"routed_subnet6",
+	"server_port",
+	"acme_domain",
+	"theme",
+	"auditing",
+	"restrict_import",
+	"reverse_proxy",
+	"pin_mode",
+}
+
+func resourceSettings() *schema.Resource {
+	return &schema.Resource{
+		Description: "The settings resource manages the instance-wide Pritunl settings. It's a singleton, and only the attributes set " +
+			"in the configuration are managed: the other settings are never changed, and removing an attribute keeps its last value.",
+		Schema: map[string]*schema.Schema{
+			"public_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Public IPv4 address or domain name of the Pritunl servers",
+			},
+			"public_address6": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Public IPv6 address or domain name of the Pritunl servers",
+			},
+			"routed_subnet6": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "IPv6 subnet routed to the Pritunl servers",
+			},
+			"server_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IsPortNumber,
+				Description:  "Port of the web server",
+			},
+			"acme_domain": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Domain of the Let's Encrypt certificate of the web server",
+			},
+			"theme": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"light", "dark"}, false),
+				Description:  "Theme of the web console, light or dark",
+			},
+			"auditing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Audit the administrator and user events",
+			},
+			"restrict_import": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Restrict the import of the user profiles to the Pritunl client",
+			},
+			"reverse_proxy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Trust the X-Forwarded-For header of a reverse proxy in front of the web server",
+			},
+			"pin_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"optional", "required", "disabled"}, false),
+				Description:  "Whether the users must, can or can't set a PIN",
+			},
+			"additional_settings": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsJSON,
+				},
+				// the values are read back as compact JSON, the suppression is applied to each key of the map
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+				Description: "Other settings as JSON encoded values by their key in the Pritunl settings, " +
+					"e.g. the pritunl-link and IPsec defaults which don't have a dedicated attribute",
+			},
+		},
+		CreateContext: resourceCreateSettings,
+		ReadContext:   resourceReadSettings,
+		UpdateContext: resourceUpdateSettings,
+		DeleteContext: resourceDeleteSettings,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportSettings,
+		},
+	}
+}
+
+func resourceReadSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	settings, err := apiClient.GetSettings()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// only the managed settings are read, the others would show up as changes removing them
+	for _, key := range settingsAttributes {
+		if _, ok := d.GetOkExists(key); ok {
+			d.Set(key, settingFromAPI(key, settings[key]))
+		}
+	}
+
+	additionalSettings := make(map[string]interface{})
+	for key := range d.Get("additional_settings").(map[string]interface{}) {
+		value, err := json.Marshal(settings[key])
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		additionalSettings[key] = string(value)
+	}
+	d.Set("additional_settings", additionalSettings)
+
+	return nil
+}
+
+func resourceCreateSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	settings, err := settingsPayload(d, false)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(settings) > 0 {
+		err = apiClient.UpdateSettings(settings)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(settingsID)
+
+	return resourceReadSettings(ctx, d, meta)
+}
+
+func resourceUpdateSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	settings, err := settingsPayload(d, true)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(settings) > 0 {
+		err = apiClient.UpdateSettings(settings)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceReadSettings(ctx, d, meta)
+}
+
+// resourceDeleteSettings only forgets the settings, they can't be removed from Pritunl
+func resourceDeleteSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+
+	return nil
+}
+
+// resourceImportSettings reads all the attributes, the read afterwards only reads the ones already in the state
+func resourceImportSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if d.Id() != settingsID {
+		return nil, fmt.Errorf("the settings must be imported with the %q ID, got %q", settingsID, d.Id())
+	}
+
+	apiClient := meta.(pritunl.Client)
+
+	settings, err := apiClient.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range settingsAttributes {
+		d.Set(key, settingFromAPI(key, settings[key]))
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// settingsPayload builds the settings to send, the attributes removed from the configuration are not reset
+func settingsPayload(d *schema.ResourceData, onlyChanged bool) (map[string]interface{}, error) {
+	settings := make(map[string]interface{})
+
+	for _, key := range settingsAttributes {
+		if onlyChanged && !d.HasChange(key) {
+			continue
+		}
+		if value, ok := d.GetOkExists(key); ok {
+			settings[key] = settingToAPI(key, value)
+		}
+	}
+
+	if !onlyChanged || d.HasChange("additional_settings") {
+		for key, value := range d.Get("additional_settings").(map[string]interface{}) {
+			for _, attribute := range settingsAttributes {
+				if key == attribute {
+					return nil, fmt.Errorf("additional_settings can't set %s, use the attribute instead", key)
+				}
+			}
+
+			var decoded interface{}
+			if err := json.Unmarshal([]byte(value.(string)), &decoded); err != nil {
+				return nil, fmt.Errorf("invalid additional_settings value of %s: %s", key, err)
+			}
+			settings[key] = decoded
+		}
+	}
+
+	return settings, nil
+}
+
+// settingToAPI converts the attribute value to the Pritunl setting, auditing is "all" or null
+func settingToAPI(key string, value interface{}) interface{} {
+	if key == "auditing" {
+		if value.(bool) {
+			return "all"
+		}
+		return nil
+	}
+
+	return value
+}
+
+func settingFromAPI(key string, value interface{}) interface{} {
+	switch key {
+	case "auditing":
+		return value == "all"
+	case "server_port":
+		if port, ok := value.(float64); ok {
+			return int(port)
+		}
+		return 0
+	}
+
+	return value
+}
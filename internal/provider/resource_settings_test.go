@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestResourceSettingsFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	fake.SetSettings(map[string]interface{}{
+		"public_address": "198.51.100.1",
+		"theme":          "dark",
+		"auditing":       "all",
+		"server_port":    float64(443),
+	})
+
+	d := applyTestResourceData(t, resourceSettings(), map[string]interface{}{
+		"public_address":  "vpn.example.com",
+		"auditing":        false,
+		"restrict_import": true,
+		"additional_settings": map[string]interface{}{
+			"ipv6": "true",
+		},
+	}, resourceCreateSettings, fake.NewClient())
+
+	expected := map[string]interface{}{
+		"public_address":  "vpn.example.com",
+		"theme":           "dark",
+		"auditing":        nil,
+		"server_port":     float64(443),
+		"restrict_import": true,
+		"ipv6":            true,
+	}
+	if settings := fake.Settings(); !reflect.DeepEqual(settings, expected) {
+		t.Fatalf("expected only the configured settings to change, got %v", settings)
+	}
+
+	if d.Id() != settingsID {
+		t.Fatalf("expected the %s ID, got %s", settingsID, d.Id())
+	}
+	if _, ok := d.GetOk("theme"); ok {
+		t.Fatalf("expected the settings missing in the configuration not to be read, got %s", d.Get("theme"))
+	}
+
+	fake.SetSettings(map[string]interface{}{"public_address": "198.51.100.2"})
+
+	if diags := resourceReadSettings(context.Background(), d, fake.NewClient()); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Get("public_address").(string) != "198.51.100.2" {
+		t.Fatalf("expected the changed setting to be read, got %s", d.Get("public_address"))
+	}
+	if d.Get("additional_settings.ipv6").(string) != "true" {
+		t.Fatalf("expected the additional setting to be read as JSON, got %s", d.Get("additional_settings.ipv6"))
+	}
+}
+
+func TestResourceSettingsAdditionalSettingsJSONFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	raw := map[string]interface{}{
+		"additional_settings": map[string]interface{}{
+			"link_defaults": "{\n  \"timeout\": 30,\n  \"hosts\": [\"a\", \"b\"]\n}\n",
+		},
+	}
+
+	d := applyTestResourceData(t, resourceSettings(), raw, resourceCreateSettings, fake.NewClient())
+
+	if value := d.Get("additional_settings.link_defaults").(string); value != `{"hosts":["a","b"],"timeout":30}` {
+		t.Fatalf("expected the setting to be read as compact JSON, got %s", value)
+	}
+
+	diff, err := resourceSettings().SimpleDiff(context.Background(), d.State(), terraform.NewResourceConfigRaw(raw), fake.NewClient())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff != nil && len(diff.Attributes) > 0 {
+		t.Fatalf("expected no changes for the equivalent JSON, got %v", diff.Attributes)
+	}
+}
+
+func TestResourceImportSettings(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	fake.SetSettings(map[string]interface{}{
+		"public_address": "198.51.100.1",
+		"theme":          "dark",
+		"auditing":       "all",
+		"server_port":    float64(443),
+	})
+
+	d := resourceSettings().Data(nil)
+
+	d.SetId("other")
+	if _, err := resourceImportSettings(context.Background(), d, fake.NewClient()); err == nil {
+		t.Fatalf("expected an error for an ID other than %s", settingsID)
+	}
+
+	d.SetId(settingsID)
+	if _, err := resourceImportSettings(context.Background(), d, fake.NewClient()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diags := resourceReadSettings(context.Background(), d, fake.NewClient()); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if d.Get("public_address").(string) != "198.51.100.1" || d.Get("theme").(string) != "dark" {
+		t.Fatalf("expected the settings to be adopted, got %s and %s", d.Get("public_address"), d.Get("theme"))
+	}
+	if !d.Get("auditing").(bool) || d.Get("server_port").(int) != 443 {
+		t.Fatalf("expected the converted settings to be adopted, got %t and %d", d.Get("auditing"), d.Get("server_port"))
+	}
+}
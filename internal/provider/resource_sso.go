@@ -0,0 +1,506 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl"
+)
+
+// ssoID is the ID of the pritunl_sso singleton
+const ssoID = "sso"
+
+// ssoPrimaryBlocks are the single sign-on providers, ssoSecondaryBlocks the secondary authentications
+var (
+	ssoPrimaryBlocks   = []string{"google", "azure", "slack", "saml", "radius"}
+	ssoSecondaryBlocks = []string{"duo", "yubico"}
+)
+
+// ssoSettingsKeys maps the attributes of the blocks to the keys of the Pritunl settings
+var ssoSettingsKeys = map[string]map[string]string{
+	"google": {
+		"domains":             "sso_match",
+		"admin_email":         "sso_google_email",
+		"service_account_key": "sso_google_key",
+	},
+	"azure": {
+		"directory_id": "sso_azure_directory_id",
+		"app_id":       "sso_azure_app_id",
+		"app_secret":   "sso_azure_app_secret",
+	},
+	"slack": {
+		"teams": "sso_match",
+	},
+	"saml": {
+		"sso_url":         "sso_saml_url",
+		"issuer_url":      "sso_saml_issuer_url",
+		"certificate":     "sso_saml_cert",
+		"okta_app_id":     "sso_okta_app_id",
+		"okta_token":      "sso_okta_token",
+		"onelogin_app_id": "sso_onelogin_app_id",
+		"onelogin_id":     "sso_onelogin_id",
+		"onelogin_secret": "sso_onelogin_secret",
+	},
+	"radius": {
+		"host":   "sso_radius_host",
+		"secret": "sso_radius_secret",
+	},
+	"duo": {
+		"integration_key": "sso_duo_token",
+		"secret_key":      "sso_duo_secret",
+		"api_hostname":    "sso_duo_host",
+		"mode":            "sso_duo_mode",
+	},
+	"yubico": {
+		"client_id":  "sso_yubico_client",
+		"secret_key": "sso_yubico_secret",
+	},
+}
+
+func resourceSSO() *schema.Resource {
+	allBlocks := append(append([]string{}, ssoPrimaryBlocks...), ssoSecondaryBlocks...)
+
+	return &schema.Resource{
+		Description: "The single sign-on resource configures the SSO provider of Pritunl and its secondary authentication. " +
+			"It's a singleton writing the sso settings, destroying it disables single sign-on.",
+		Schema: map[string]*schema.Schema{
+			"default_organization_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Organization the single sign-on users are added to",
+			},
+			"cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Cache the single sign-on authentications of the users",
+			},
+			"client_cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Cache the single sign-on authentications in the Pritunl client",
+			},
+			"google": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: ssoConflicts("google", ssoPrimaryBlocks),
+				AtLeastOneOf:  allBlocks,
+				Description:   "Google single sign-on",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"domains": {
+							Type:        schema.TypeList,
+							Required:    true,
+							MinItems:    1,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Google Workspace domains the users are allowed from",
+						},
+						"admin_email": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							RequiredWith: []string{"google.0.service_account_key"},
+							Description:  "Email of a Google Workspace administrator, used to read the groups of the users",
+						},
+						"service_account_key": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Sensitive:    true,
+							RequiredWith: []string{"google.0.admin_email"},
+							Description:  "JSON key of the service account reading the groups of the users",
+						},
+					},
+				},
+			},
+			"azure": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: ssoConflicts("azure", ssoPrimaryBlocks),
+				AtLeastOneOf:  allBlocks,
+				Description:   "Azure AD single sign-on",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"directory_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Directory (tenant) ID",
+						},
+						"app_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Application (client) ID",
+						},
+						"app_secret": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "Client secret of the application",
+						},
+					},
+				},
+			},
+			"slack": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: ssoConflicts("slack", ssoPrimaryBlocks),
+				AtLeastOneOf:  allBlocks,
+				Description:   "Slack single sign-on",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"teams": {
+							Type:        schema.TypeList,
+							Required:    true,
+							MinItems:    1,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Slack teams the users are allowed from",
+						},
+					},
+				},
+			},
+			"saml": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: ssoConflicts("saml", ssoPrimaryBlocks),
+				AtLeastOneOf:  allBlocks,
+				Description:   "SAML single sign-on, with the Okta or OneLogin API when their attributes are set",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"sso_url": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.IsURLWithHTTPS,
+							Description:  "Single sign-on URL of the identity provider",
+						},
+						"issuer_url": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Issuer URL of the identity provider",
+						},
+						"certificate": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "PEM encoded certificate of the identity provider",
+						},
+						"okta_app_id": {
+							Type:          schema.TypeString,
+							Optional:      true,
+							RequiredWith:  []string{"saml.0.okta_token"},
+							ConflictsWith: []string{"saml.0.onelogin_app_id"},
+							Description:   "ID of the Okta application",
+						},
+						"okta_token": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Sensitive:    true,
+							RequiredWith: []string{"saml.0.okta_app_id"},
+							Description:  "Okta API token",
+						},
+						"onelogin_app_id": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							RequiredWith: []string{"saml.0.onelogin_id", "saml.0.onelogin_secret"},
+							Description:  "ID of the OneLogin application",
+						},
+						"onelogin_id": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							RequiredWith: []string{"saml.0.onelogin_app_id"},
+							Description:  "OneLogin API client ID",
+						},
+						"onelogin_secret": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Sensitive:    true,
+							RequiredWith: []string{"saml.0.onelogin_app_id"},
+							Description:  "OneLogin API client secret",
+						},
+					},
+				},
+			},
+			"radius": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: ssoConflicts("radius", ssoPrimaryBlocks),
+				AtLeastOneOf:  allBlocks,
+				Description:   "RADIUS authentication",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Host of the RADIUS server, with an optional port",
+						},
+						"secret": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "Shared secret of the RADIUS server",
+						},
+					},
+				},
+			},
+			"duo": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"yubico"},
+				AtLeastOneOf:  allBlocks,
+				Description:   "Duo secondary authentication, or the only authentication without a single sign-on provider",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"integration_key": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Integration key of the Duo application",
+						},
+						"secret_key": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "Secret key of the Duo application",
+						},
+						"api_hostname": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "API hostname of the Duo application",
+						},
+						"mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "push",
+							ValidateFunc: validation.StringInSlice([]string{"push", "phone", "push_phone", "passcode"}, false),
+							Description:  "Duo authentication method",
+						},
+					},
+				},
+			},
+			"yubico": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"duo", "radius"},
+				AtLeastOneOf:  allBlocks,
+				Description:   "YubiKey secondary authentication, or the only authentication without a single sign-on provider",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"client_id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Yubico API client ID",
+						},
+						"secret_key": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "Yubico API secret key",
+						},
+					},
+				},
+			},
+			"mode": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Single sign-on mode of Pritunl, matching the auth_type of the users",
+			},
+		},
+		CreateContext: resourceCreateSSO,
+		ReadContext:   resourceReadSSO,
+		UpdateContext: resourceUpdateSSO,
+		DeleteContext: resourceDeleteSSO,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceImportSSO,
+		},
+	}
+}
+
+// ssoConflicts returns the other blocks of the group
+func ssoConflicts(block string, group []string) []string {
+	conflicts := make([]string, 0, len(group)-1)
+	for _, other := range group {
+		if other != block {
+			conflicts = append(conflicts, other)
+		}
+	}
+
+	return conflicts
+}
+
+func resourceReadSSO(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	settings, err := apiClient.GetSettings()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	mode, _ := settings["sso"].(string)
+	primary, secondary := parseSSOMode(mode)
+
+	d.Set("mode", mode)
+	d.Set("default_organization_id", settings["sso_org"])
+	d.Set("cache", settings["sso_cache"] == true)
+	d.Set("client_cache", settings["sso_client_cache"] == true)
+
+	for _, block := range append(append([]string{}, ssoPrimaryBlocks...), ssoSecondaryBlocks...) {
+		if block != primary && block != secondary {
+			d.Set(block, nil)
+			continue
+		}
+
+		attributes := resourceSSO().Schema[block].Elem.(*schema.Resource).Schema
+		values := make(map[string]interface{})
+		for attribute, key := range ssoSettingsKeys[block] {
+			value := settings[key]
+
+			// the secrets are not returned by the API and are kept, the other attributes are read as they are
+			if (value == nil || value == "") && attributes[attribute].Sensitive {
+				value = d.Get(fmt.Sprintf("%s.0.%s", block, attribute))
+			}
+			values[attribute] = value
+		}
+		d.Set(block, []interface{}{values})
+	}
+
+	return nil
+}
+
+func resourceCreateSSO(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := writeSSOSettings(d, meta); diags.HasError() {
+		return diags
+	}
+
+	d.SetId(ssoID)
+
+	return resourceReadSSO(ctx, d, meta)
+}
+
+func resourceUpdateSSO(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := writeSSOSettings(d, meta); diags.HasError() {
+		return diags
+	}
+
+	return resourceReadSSO(ctx, d, meta)
+}
+
+// resourceDeleteSSO disables single sign-on, the settings of the providers are kept
+func resourceDeleteSSO(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	err := apiClient.UpdateSettings(map[string]interface{}{"sso": nil})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourceImportSSO(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if d.Id() != ssoID {
+		return nil, fmt.Errorf("the single sign-on settings must be imported with the %q ID, got %q", ssoID, d.Id())
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// writeSSOSettings sends the mode and the settings of the configured blocks, the settings of the other providers are kept
+func writeSSOSettings(d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := meta.(pritunl.Client)
+
+	primary, secondary := "", ""
+	for _, block := range ssoPrimaryBlocks {
+		if d.Get(block+".#").(int) > 0 {
+			primary = block
+		}
+	}
+	for _, block := range ssoSecondaryBlocks {
+		if d.Get(block+".#").(int) > 0 {
+			secondary = block
+		}
+	}
+
+	if primary == "saml" {
+		if d.Get("saml.0.okta_app_id").(string) != "" {
+			primary = "saml_okta"
+		} else if d.Get("saml.0.onelogin_app_id").(string) != "" {
+			primary = "saml_onelogin"
+		}
+	}
+
+	mode, err := ssoMode(primary, secondary)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	settings := map[string]interface{}{
+		"sso":              mode,
+		"sso_org":          d.Get("default_organization_id").(string),
+		"sso_cache":        d.Get("cache").(bool),
+		"sso_client_cache": d.Get("client_cache").(bool),
+	}
+	for _, block := range []string{primaryBlock(primary), secondary} {
+		if block == "" {
+			continue
+		}
+		for attribute, key := range ssoSettingsKeys[block] {
+			settings[key] = d.Get(fmt.Sprintf("%s.0.%s", block, attribute))
+		}
+	}
+
+	err = apiClient.UpdateSettings(settings)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// ssoMode combines the single sign-on provider with the secondary authentication the way Pritunl names the modes
+func ssoMode(primary, secondary string) (string, error) {
+	switch {
+	case primary == "" && secondary == "":
+		return "", fmt.Errorf("a single sign-on provider or a secondary authentication is required")
+	case primary == "":
+		return secondary, nil
+	case secondary == "":
+		return primary, nil
+	case primary == "radius" && secondary == "yubico":
+		return "", fmt.Errorf("RADIUS can't be combined with the YubiKey authentication")
+	}
+
+	return primary + "_" + secondary, nil
+}
+
+// parseSSOMode splits the mode into the block of the provider and the block of the secondary authentication
+func parseSSOMode(mode string) (primary string, secondary string) {
+	for _, block := range ssoSecondaryBlocks {
+		if mode == block {
+			return "", block
+		}
+		if strings.HasSuffix(mode, "_"+block) {
+			return primaryBlock(strings.TrimSuffix(mode, "_"+block)), block
+		}
+	}
+
+	return primaryBlock(mode), ""
+}
+
+// primaryBlock returns the block of the provider mode, the Okta and OneLogin modes are configured in the saml block
+func primaryBlock(mode string) string {
+	if strings.HasPrefix(mode, "saml") {
+		return "saml"
+	}
+
+	return mode
+}
@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/next-gen-infrastructure/terraform-provider-pritunl/internal/pritunl/pritunltest"
+)
+
+func TestResourceSSOFromFakeServer(t *testing.T) {
+	fake := pritunltest.NewTestServer(t)
+
+	fake.SetSettings(map[string]interface{}{
+		"theme":             "dark",
+		"sso_radius_host":   "radius.internal",
+		"sso_duo_secret":    "",
+		"sso_okta_token":    "",
+		"sso_saml_cert":     "",
+		"sso_yubico_client": "kept",
+	})
+
+	d := applyTestResourceData(t, resourceSSO(), map[string]interface{}{
+		"default_organization_id": "5f3e2b1c4d9a8e7f6b5c4d3e",
+		"cache":                   true,
+		"saml": []interface{}{map[string]interface{}{
+			"sso_url":     "https://example.okta.com/app/sso/saml",
+			"issuer_url":  "http://www.okta.com/exk1",
+			"certificate": "-----BEGIN CERTIFICATE-----",
+			"okta_app_id": "0oa1",
+			"okta_token":  "okta-token",
+		}},
+		"duo": []interface{}{map[string]interface{}{
+			"integration_key": "DIXXXXXXXXXXXXXXXXXX",
+			"secret_key":      "duo-secret",
+			"api_hostname":    "api-1234.duosecurity.com",
+		}},
+	}, resourceCreateSSO, fake.NewClient())
+
+	settings := fake.Settings()
+	expected := map[string]interface{}{
+		"sso":               "saml_okta_duo",
+		"sso_org":           "5f3e2b1c4d9a8e7f6b5c4d3e",
+		"sso_cache":         true,
+		"sso_client_cache":  false,
+		"sso_saml_url":      "https://example.okta.com/app/sso/saml",
+		"sso_okta_token":    "okta-token",
+		"sso_duo_token":     "DIXXXXXXXXXXXXXXXXXX",
+		"sso_duo_mode":      "push",
+		"theme":             "dark",
+		"sso_radius_host":   "radius.internal",
+		"sso_yubico_client": "kept",
+	}
+	for key, value := range expected {
+		if settings[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, settings[key])
+		}
+	}
+
+	if d.Id() != ssoID || d.Get("mode").(string) != "saml_okta_duo" {
+		t.Fatalf("expected the %s ID and the saml_okta_duo mode, got %s and %s", ssoID, d.Id(), d.Get("mode"))
+	}
+
+	// the API doesn't return the secrets
+	fake.SetSettings(map[string]interface{}{
+		"sso":                 "saml_okta_duo",
+		"sso_org":             "5f3e2b1c4d9a8e7f6b5c4d3e",
+		"sso_saml_url":        "https://example.okta.com/app/sso/saml",
+		"sso_saml_issuer_url": "http://www.okta.com/exk2",
+		"sso_okta_app_id":     "0oa1",
+		"sso_duo_token":       "DIXXXXXXXXXXXXXXXXXX",
+		"sso_duo_host":        "api-1234.duosecurity.com",
+		"sso_duo_mode":        "push",
+	})
+
+	if diags := resourceReadSSO(context.Background(), d, fake.NewClient()); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Get("saml.0.issuer_url").(string) != "http://www.okta.com/exk2" {
+		t.Fatalf("expected the changed issuer to be read, got %s", d.Get("saml.0.issuer_url"))
+	}
+	if d.Get("saml.0.okta_token").(string) != "okta-token" || d.Get("duo.0.secret_key").(string) != "duo-secret" {
+		t.Fatalf("expected the secrets to be kept")
+	}
+
+	// a setting cleared outside of Terraform is read, unlike a secret
+	fake.SetSettings(map[string]interface{}{
+		"sso_duo_host": "",
+	})
+
+	if diags := resourceReadSSO(context.Background(), d, fake.NewClient()); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Get("duo.0.api_hostname").(string) != "" {
+		t.Fatalf("expected the cleared API hostname to be read, got %s", d.Get("duo.0.api_hostname"))
+	}
+	if d.Get("duo.0.secret_key").(string) != "duo-secret" {
+		t.Fatalf("expected the secret to be kept")
+	}
+
+	if diags := resourceDeleteSSO(context.Background(), d, fake.NewClient()); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if settings := fake.Settings(); settings["sso"] != nil || settings["sso_saml_url"] == nil {
+		t.Fatalf("expected single sign-on to be disabled and its settings kept, got %v", settings)
+	}
+}
+
+func TestResourceSSOValidation(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"without a provider": {
+			"default_organization_id": "5f3e2b1c4d9a8e7f6b5c4d3e",
+		},
+		"with two providers": {
+			"default_organization_id": "5f3e2b1c4d9a8e7f6b5c4d3e",
+			"slack":                   []interface{}{map[string]interface{}{"teams": []interface{}{"example"}}},
+			"radius":                  []interface{}{map[string]interface{}{"host": "radius.internal", "secret": "secret"}},
+		},
+		"with duo and yubico": {
+			"default_organization_id": "5f3e2b1c4d9a8e7f6b5c4d3e",
+			"duo": []interface{}{map[string]interface{}{
+				"integration_key": "DIXXXXXXXXXXXXXXXXXX",
+				"secret_key":      "duo-secret",
+				"api_hostname":    "api-1234.duosecurity.com",
+			}},
+			"yubico": []interface{}{map[string]interface{}{"client_id": "1", "secret_key": "secret"}},
+		},
+		"with duo missing its keys": {
+			"default_organization_id": "5f3e2b1c4d9a8e7f6b5c4d3e",
+			"duo":                     []interface{}{map[string]interface{}{"integration_key": "DIXXXXXXXXXXXXXXXXXX"}},
+		},
+		"with radius and yubico": {
+			"default_organization_id": "5f3e2b1c4d9a8e7f6b5c4d3e",
+			"radius":                  []interface{}{map[string]interface{}{"host": "radius.internal", "secret": "secret"}},
+			"yubico":                  []interface{}{map[string]interface{}{"client_id": "1", "secret_key": "secret"}},
+		},
+		"with okta and onelogin": {
+			"default_organization_id": "5f3e2b1c4d9a8e7f6b5c4d3e",
+			"saml": []interface{}{map[string]interface{}{
+				"sso_url":         "https://example.okta.com/app/sso/saml",
+				"issuer_url":      "http://www.okta.com/exk1",
+				"certificate":     "-----BEGIN CERTIFICATE-----",
+				"okta_app_id":     "0oa1",
+				"okta_token":      "okta-token",
+				"onelogin_app_id": "1",
+				"onelogin_id":     "1",
+				"onelogin_secret": "secret",
+			}},
+		},
+	}
+
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diags := resourceSSO().Validate(terraform.NewResourceConfigRaw(raw)); !diags.HasError() {
+				t.Fatalf("expected a validation error")
+			}
+		})
+	}
+
+	valid := map[string]interface{}{
+		"default_organization_id": "5f3e2b1c4d9a8e7f6b5c4d3e",
+		"google":                  []interface{}{map[string]interface{}{"domains": []interface{}{"example.com"}}},
+		"yubico":                  []interface{}{map[string]interface{}{"client_id": "1", "secret_key": "secret"}},
+	}
+	if diags := resourceSSO().Validate(terraform.NewResourceConfigRaw(valid)); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+}
+
+func TestSSOMode(t *testing.T) {
+	tests := []struct {
+		primary   string
+		secondary string
+		mode      string
+	}{
+		{"google", "", "google"},
+		{"saml_onelogin", "yubico", "saml_onelogin_yubico"},
+		{"radius", "duo", "radius_duo"},
+		{"", "duo", "duo"},
+		{"", "yubico", "yubico"},
+	}
+
+	for _, test := range tests {
+		mode, err := ssoMode(test.primary, test.secondary)
+		if err != nil || mode != test.mode {
+			t.Fatalf("expected %s for %s and %s, got %s (%v)", test.mode, test.primary, test.secondary, mode, err)
+		}
+
+		primary, secondary := parseSSOMode(mode)
+		if primary != primaryBlock(test.primary) || secondary != test.secondary {
+			t.Fatalf("expected %s to be parsed to %s and %s, got %s and %s", mode, test.primary, test.secondary, primary, secondary)
+		}
+	}
+
+	if _, err := ssoMode("radius", "yubico"); err == nil {
+		t.Fatalf("expected an error for RADIUS with YubiKey")
+	}
+	if _, err := ssoMode("", ""); err == nil {
+		t.Fatalf("expected an error without a provider")
+	}
+}
@@ -30,7 +30,7 @@ func resourceUser() *schema.Resource {
 				},
 			},
 			"groups": {
-				Type: schema.TypeList,
+				Type: schema.TypeSet,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -58,7 +58,9 @@ func resourceUser() *schema.Resource {
 			"network_links": {
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
+					Type:             schema.TypeString,
+					ValidateFunc:     validateCIDRNetwork,
+					DiffSuppressFunc: suppressEquivalentNetworkDiff,
 				},
 				Optional:    true,
 				Description: "Network address with cidr subnet. This will provision access to a clients local network to the attached vpn servers and other clients. Multiple networks may be separated by a comma. Router must have a static route to VPN virtual network through client.",
@@ -76,7 +78,7 @@ func resourceUser() *schema.Resource {
 				ValidateFunc: validation.StringInSlice([]string{"local", "duo", "yubico", "azure", "azure_duo", "azure_yubico", "google", "google_duo", "google_yubico", "slack", "slack_duo", "slack_yubico", "saml", "saml_duo", "saml_yubico", "saml_okta", "saml_okta_duo", "saml_okta_yubico", "saml_onelogin", "saml_onelogin_duo", "saml_onelogin_yubico", "radius", "radius_duo", "plugin"}, false),
 			},
 			"mac_addresses": {
-				Type: schema.TypeList,
+				Type: schema.TypeSet,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
@@ -101,6 +103,12 @@ func resourceUser() *schema.Resource {
 				Optional:    true,
 				Description: "Bypass secondary authentication such as the PIN and two-factor authentication. Use for server users that can't provide a two-factor code.",
 			},
+			"audit": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Record the audit events of the user, e.g. the logins and profile downloads. The events are returned by the pritunl_user_audit data source.",
+			},
 			"pin": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -108,6 +116,11 @@ func resourceUser() *schema.Resource {
 				Description: "The PIN for user authentication.",
 			},
 		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			stateUpgrader(0, resourceUserV0(), listToSet("groups", "mac_addresses")),
+		},
+		CustomizeDiff: resourceUserCustomizeDiff,
 		CreateContext: resourceUserCreate,
 		ReadContext:   resourceUserRead,
 		UpdateContext: resourceUserUpdate,
@@ -118,6 +131,26 @@ func resourceUser() *schema.Resource {
 	}
 }
 
+func resourceUserCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("network_links") {
+		return nil
+	}
+
+	validator := clientNetworkValidator(meta)
+	for _, v := range d.Get("network_links").([]interface{}) {
+		network, ok := v.(string)
+		if !ok || network == "" {
+			continue
+		}
+
+		if err := validator.validate(network, true); err != nil {
+			return fmt.Errorf("invalid network_links: %s", err)
+		}
+	}
+
+	return nil
+}
+
 func resourceUserRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	apiClient := meta.(pritunl.Client)
 
@@ -137,21 +170,10 @@ func resourceUserRead(_ context.Context, d *schema.ResourceData, meta interface{
 	d.Set("client_to_client", user.ClientToClient)
 	d.Set("mac_addresses", user.MacAddresses)
 	d.Set("bypass_secondary", user.BypassSecondary)
+	d.Set("audit", user.Audit)
 	d.Set("organization_id", user.Organization)
 
-	if len(user.Groups) > 0 {
-		groupsList := make([]string, 0)
-
-		for _, group := range user.Groups {
-			groupsList = append(groupsList, group)
-		}
-
-		declaredGroups, ok := d.Get("groups").([]interface{})
-		if !ok {
-			return diag.Errorf("failed to parse groups for the user: %s", user.Name)
-		}
-		d.Set("groups", matchStringEntitiesWithSchema(groupsList, declaredGroups))
-	}
+	d.Set("groups", user.Groups)
 
 	return nil
 }
@@ -193,7 +215,7 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 
 	if d.HasChange("groups") {
 		groups := make([]string, 0)
-		for _, v := range d.Get("groups").([]interface{}) {
+		for _, v := range d.Get("groups").(*schema.Set).List() {
 			groups = append(groups, v.(string))
 		}
 		user.Groups = groups
@@ -219,7 +241,7 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 	if d.HasChange("network_links") {
 		networkLinks := make([]string, 0)
 		for _, v := range d.Get("network_links").([]interface{}) {
-			networkLinks = append(networkLinks, v.(string))
+			networkLinks = append(networkLinks, pritunl.NormalizeNetwork(v.(string)))
 		}
 		user.NetworkLinks = networkLinks
 	}
@@ -234,7 +256,7 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 
 	if d.HasChange("mac_addresses") {
 		macAddresses := make([]string, 0)
-		for _, v := range d.Get("mac_addresses").([]interface{}) {
+		for _, v := range d.Get("mac_addresses").(*schema.Set).List() {
 			macAddresses = append(macAddresses, v.(string))
 		}
 		user.MacAddresses = macAddresses
@@ -256,6 +278,10 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 		user.BypassSecondary = v.(bool)
 	}
 
+	if d.HasChange("audit") {
+		user.Audit = d.Get("audit").(bool)
+	}
+
 	err = apiClient.UpdateUser(d.Id(), user)
 	if err != nil {
 		return diag.FromErr(err)
@@ -273,13 +299,13 @@ func resourceUserCreate(_ context.Context, d *schema.ResourceData, meta interfac
 	}
 
 	macAddresses := make([]string, 0)
-	for _, v := range d.Get("mac_addresses").([]interface{}) {
+	for _, v := range d.Get("mac_addresses").(*schema.Set).List() {
 		macAddresses = append(macAddresses, v.(string))
 	}
 
 	networkLinks := make([]string, 0)
 	for _, v := range d.Get("network_links").([]interface{}) {
-		networkLinks = append(networkLinks, v.(string))
+		networkLinks = append(networkLinks, pritunl.NormalizeNetwork(v.(string)))
 	}
 
 	portForwarding := make([]map[string]interface{}, 0)
@@ -288,7 +314,7 @@ func resourceUserCreate(_ context.Context, d *schema.ResourceData, meta interfac
 	}
 
 	groups := make([]string, 0)
-	for _, v := range d.Get("groups").([]interface{}) {
+	for _, v := range d.Get("groups").(*schema.Set).List() {
 		groups = append(groups, v.(string))
 	}
 
@@ -305,6 +331,7 @@ func resourceUserCreate(_ context.Context, d *schema.ResourceData, meta interfac
 		ClientToClient:  d.Get("client_to_client").(bool),
 		MacAddresses:    macAddresses,
 		BypassSecondary: d.Get("bypass_secondary").(bool),
+		Audit:           d.Get("audit").(bool),
 		Groups:          groups,
 	}
 
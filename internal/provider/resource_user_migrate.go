@@ -0,0 +1,26 @@
+package provider
+
+import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+// resourceUserV0 is the schema of the pritunl_user resource before
+// groups and mac_addresses were converted to sets
+func resourceUserV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":             {Type: schema.TypeString, Required: true},
+			"organization_id":  {Type: schema.TypeString, Required: true},
+			"groups":           {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}, Optional: true},
+			"email":            {Type: schema.TypeString, Optional: true},
+			"disabled":         {Type: schema.TypeBool, Optional: true},
+			"port_forwarding":  {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeMap}, Optional: true},
+			"network_links":    {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}, Optional: true},
+			"client_to_client": {Type: schema.TypeBool, Optional: true},
+			"auth_type":        {Type: schema.TypeString, Optional: true, Computed: true},
+			"mac_addresses":    {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}, Optional: true},
+			"dns_servers":      {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}, Optional: true},
+			"dns_suffix":       {Type: schema.TypeString, Optional: true},
+			"bypass_secondary": {Type: schema.TypeBool, Optional: true},
+			"pin":              {Type: schema.TypeString, Optional: true, Sensitive: true},
+		},
+	}
+}
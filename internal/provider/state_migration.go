@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// stateMigration changes the raw state of a resource in place
+type stateMigration func(rawState map[string]interface{}) error
+
+// stateUpgrader builds the upgrader of the state stored with the given schema version
+// to the next version by applying the migrations in order.
+// The previous schema must be frozen, it must not be derived from the current schema of the resource.
+func stateUpgrader(version int, previous *schema.Resource, migrations ...stateMigration) schema.StateUpgrader {
+	return schema.StateUpgrader{
+		Version: version,
+		Type:    previous.CoreConfigSchema().ImpliedType(),
+		Upgrade: func(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+			if rawState == nil {
+				rawState = make(map[string]interface{})
+			}
+
+			for _, migration := range migrations {
+				if err := migration(rawState); err != nil {
+					return nil, fmt.Errorf("failed to upgrade the state from version %d: %w", version, err)
+				}
+			}
+
+			return rawState, nil
+		},
+	}
+}
+
+// renameAttribute moves the value of the attribute to the new name
+func renameAttribute(oldName, newName string) stateMigration {
+	return func(rawState map[string]interface{}) error {
+		value, ok := rawState[oldName]
+		if !ok {
+			return nil
+		}
+
+		if _, found := rawState[newName]; found && rawState[newName] != nil {
+			return fmt.Errorf("can't rename %s to %s, the attribute already exists", oldName, newName)
+		}
+
+		rawState[newName] = value
+		delete(rawState, oldName)
+
+		return nil
+	}
+}
+
+// listToSet removes empty and duplicated values of the list attributes which became sets
+func listToSet(keys ...string) stateMigration {
+	return func(rawState map[string]interface{}) error {
+		for _, key := range keys {
+			list, ok := rawState[key].([]interface{})
+			if !ok {
+				continue
+			}
+
+			seen := make(map[interface{}]struct{}, len(list))
+			set := make([]interface{}, 0, len(list))
+			for _, v := range list {
+				if v == nil || v == "" {
+					continue
+				}
+				if _, found := seen[v]; found {
+					continue
+				}
+				seen[v] = struct{}{}
+				set = append(set, v)
+			}
+
+			rawState[key] = set
+		}
+
+		return nil
+	}
+}
+
+// splitID splits the composite value of the attribute (usually "id") by the separator
+// and stores the parts in the given attributes. Attributes with an empty name are skipped.
+func splitID(key, separator string, targets ...string) stateMigration {
+	return func(rawState map[string]interface{}) error {
+		value, ok := rawState[key].(string)
+		if !ok || value == "" {
+			return nil
+		}
+
+		parts := strings.Split(value, separator)
+		if len(parts) != len(targets) {
+			return fmt.Errorf("%s %q must consist of %d parts separated by %q", key, value, len(targets), separator)
+		}
+
+		for i, target := range targets {
+			if target != "" {
+				rawState[target] = parts[i]
+			}
+		}
+
+		return nil
+	}
+}
+
+// moveNestedBlock moves the value found at the "from" path to the "to" path.
+// Paths consist of attribute names and list indexes separated by dots, e.g. "settings.0.routes",
+// missing blocks of the destination path are created with a single element.
+func moveNestedBlock(from, to string) stateMigration {
+	return func(rawState map[string]interface{}) error {
+		value, ok, err := removeStatePath(rawState, strings.Split(from, "."))
+		if err != nil {
+			return fmt.Errorf("can't move %s: %w", from, err)
+		}
+		if !ok {
+			return nil
+		}
+
+		if err := setStatePath(rawState, strings.Split(to, "."), value); err != nil {
+			return fmt.Errorf("can't move %s to %s: %w", from, to, err)
+		}
+
+		return nil
+	}
+}
+
+// removeStatePath removes the value at the path and returns it
+func removeStatePath(rawState map[string]interface{}, path []string) (interface{}, bool, error) {
+	var current interface{} = rawState
+
+	for i, step := range path {
+		last := i == len(path)-1
+
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[step]
+			if !ok {
+				return nil, false, nil
+			}
+			if last {
+				delete(node, step)
+				return value, true, nil
+			}
+			current = value
+		case []interface{}:
+			index, err := strconv.Atoi(step)
+			if err != nil {
+				return nil, false, fmt.Errorf("%s is not a list index", step)
+			}
+			if index < 0 || index >= len(node) {
+				return nil, false, nil
+			}
+			if last {
+				return nil, false, fmt.Errorf("removing list elements isn't supported")
+			}
+			current = node[index]
+		case nil:
+			return nil, false, nil
+		default:
+			return nil, false, fmt.Errorf("%s is not a block", strings.Join(path[:i], "."))
+		}
+	}
+
+	return nil, false, nil
+}
+
+// setStatePath sets the value at the path creating missing blocks on the way
+func setStatePath(node map[string]interface{}, path []string, value interface{}) error {
+	step := path[0]
+	if len(path) == 1 {
+		node[step] = value
+		return nil
+	}
+
+	if index, err := strconv.Atoi(path[1]); err == nil {
+		list, _ := node[step].([]interface{})
+		for len(list) <= index {
+			list = append(list, map[string]interface{}{})
+		}
+		node[step] = list
+
+		if len(path) == 2 {
+			list[index] = value
+			return nil
+		}
+
+		block, ok := list[index].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s.%s is not a block", step, path[1])
+		}
+		return setStatePath(block, path[2:], value)
+	}
+
+	block, ok := node[step].(map[string]interface{})
+	if !ok {
+		if node[step] != nil {
+			return fmt.Errorf("%s is not a block", step)
+		}
+		block = make(map[string]interface{})
+		node[step] = block
+	}
+
+	return setStatePath(block, path[1:], value)
+}
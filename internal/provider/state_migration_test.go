@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// TestResourceStateUpgradeFixtures loads the golden state of every schema version
+// from testdata/state/<resource>/v<version>.json, upgrades it to the current version
+// and compares the result with the golden state of the current version.
+// Every resource has the golden state of its current version, so schema changes without a migration are caught.
+func TestResourceStateUpgradeFixtures(t *testing.T) {
+	resources := Provider().ResourcesMap
+
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		r := resources[name]
+
+		t.Run(name, func(t *testing.T) {
+			expected := loadStateFixture(t, name, r.SchemaVersion)
+			checkStateConformsToSchema(t, expected, r)
+
+			if len(r.StateUpgraders) != r.SchemaVersion {
+				t.Fatalf("expected %d state upgraders, got %d", r.SchemaVersion, len(r.StateUpgraders))
+			}
+
+			for i, upgrader := range r.StateUpgraders {
+				if upgrader.Version != i {
+					t.Fatalf("expected state upgrader #%d to upgrade version %d, got %d", i, i, upgrader.Version)
+				}
+
+				t.Run(fmt.Sprintf("v%d", upgrader.Version), func(t *testing.T) {
+					rawState := loadStateFixture(t, name, upgrader.Version)
+					if _, err := ctyjson.Unmarshal(mustMarshalState(t, rawState), upgrader.Type); err != nil {
+						t.Fatalf("fixture of version %d doesn't match the schema of the version: %s", upgrader.Version, err)
+					}
+
+					for _, next := range r.StateUpgraders[i:] {
+						var err error
+						rawState, err = next.Upgrade(context.Background(), rawState, nil)
+						if err != nil {
+							t.Fatalf("failed to upgrade the state from version %d: %s", next.Version, err)
+						}
+					}
+
+					if !reflect.DeepEqual(expected, rawState) {
+						t.Fatalf("expected upgraded state:\n%s\ngot:\n%s", mustMarshalState(t, expected), mustMarshalState(t, rawState))
+					}
+				})
+			}
+		})
+	}
+}
+
+func loadStateFixture(t *testing.T, name string, version int) map[string]interface{} {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "state", name, fmt.Sprintf("v%d.json", version)))
+	if err != nil {
+		t.Fatalf("missing state fixture of version %d: %s", version, err)
+	}
+
+	rawState := make(map[string]interface{})
+	if err := json.Unmarshal(data, &rawState); err != nil {
+		t.Fatalf("invalid state fixture of version %d: %s", version, err)
+	}
+
+	return rawState
+}
+
+func checkStateConformsToSchema(t *testing.T, rawState map[string]interface{}, r *schema.Resource) {
+	t.Helper()
+
+	if _, err := ctyjson.Unmarshal(mustMarshalState(t, rawState), r.CoreConfigSchema().ImpliedType()); err != nil {
+		t.Fatalf("state fixture doesn't match the current schema: %s", err)
+	}
+}
+
+func mustMarshalState(t *testing.T, rawState map[string]interface{}) []byte {
+	t.Helper()
+
+	data, err := json.MarshalIndent(rawState, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal the state: %s", err)
+	}
+
+	return data
+}
+
+func TestRenameAttribute(t *testing.T) {
+	rawState := map[string]interface{}{"organization": "abc"}
+	if err := renameAttribute("organization", "organization_id")(rawState); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(rawState, map[string]interface{}{"organization_id": "abc"}) {
+		t.Fatalf("unexpected state: %v", rawState)
+	}
+
+	rawState = map[string]interface{}{"organization": "abc", "organization_id": "def"}
+	if err := renameAttribute("organization", "organization_id")(rawState); err == nil {
+		t.Fatalf("expected an error when the new attribute already exists")
+	}
+}
+
+func TestListToSet(t *testing.T) {
+	rawState := map[string]interface{}{
+		"groups": []interface{}{"b", "", "a", "b", nil},
+		"other":  []interface{}{"b", "b"},
+	}
+	if err := listToSet("groups", "missing")(rawState); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"groups": []interface{}{"b", "a"},
+		"other":  []interface{}{"b", "b"},
+	}
+	if !reflect.DeepEqual(rawState, expected) {
+		t.Fatalf("expected state %v, got %v", expected, rawState)
+	}
+}
+
+func TestSplitID(t *testing.T) {
+	rawState := map[string]interface{}{"id": "5f6dba9b27f4af4d7a1f5c22"}
+	if err := splitID("id", "/", "organization_id", "user_id")(rawState); err == nil {
+		t.Fatalf("expected an error for an id with an unexpected number of parts")
+	}
+
+	rawState = map[string]interface{}{"id": "5f6dba9b27f4af4d7a1f5c22/60cd0c0b7723cf3c91146891"}
+	if err := splitID("id", "/", "organization_id", "user_id")(rawState); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"id":              "5f6dba9b27f4af4d7a1f5c22/60cd0c0b7723cf3c91146891",
+		"organization_id": "5f6dba9b27f4af4d7a1f5c22",
+		"user_id":         "60cd0c0b7723cf3c91146891",
+	}
+	if !reflect.DeepEqual(rawState, expected) {
+		t.Fatalf("expected state %v, got %v", expected, rawState)
+	}
+}
+
+func TestMoveNestedBlock(t *testing.T) {
+	testCases := []struct {
+		name     string
+		from     string
+		to       string
+		state    string
+		expected string
+	}{
+		{
+			name:     "moves a nested attribute to the top level",
+			from:     "settings.0.route",
+			to:       "route",
+			state:    `{"settings": [{"route": [{"network": "10.0.0.0/24"}], "debug": true}]}`,
+			expected: `{"settings": [{"debug": true}], "route": [{"network": "10.0.0.0/24"}]}`,
+		},
+		{
+			name:     "moves a top level attribute into a new block",
+			from:     "debug",
+			to:       "settings.0.debug",
+			state:    `{"debug": true}`,
+			expected: `{"settings": [{"debug": true}]}`,
+		},
+		{
+			name:     "moves a block into a map",
+			from:     "route",
+			to:       "settings.routes",
+			state:    `{"route": [{"network": "10.0.0.0/24"}], "settings": {"debug": true}}`,
+			expected: `{"settings": {"debug": true, "routes": [{"network": "10.0.0.0/24"}]}}`,
+		},
+		{
+			name:     "ignores a missing block",
+			from:     "settings.0.route",
+			to:       "route",
+			state:    `{"settings": []}`,
+			expected: `{"settings": []}`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			rawState := make(map[string]interface{})
+			expected := make(map[string]interface{})
+			if err := json.Unmarshal([]byte(testCase.state), &rawState); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(testCase.expected), &expected); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := moveNestedBlock(testCase.from, testCase.to)(rawState); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(rawState, expected) {
+				t.Fatalf("expected state %v, got %v", expected, rawState)
+			}
+		})
+	}
+}
+
+func TestStateUpgraderWrapsErrors(t *testing.T) {
+	upgrader := stateUpgrader(2, resourceUserV0(), splitID("id", "/", "organization_id", "user_id"))
+
+	_, err := upgrader.Upgrade(context.Background(), map[string]interface{}{"id": "abc"}, nil)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+}